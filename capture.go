@@ -0,0 +1,182 @@
+package subprocess
+
+import (
+	"bytes"
+	"os"
+	"sync"
+)
+
+// CapturePolicy is the type of the Capture.Policy field.  It defines the standard output or standard error stream data
+// that is held in a Response when the stream exceeds the Capture.Limit.
+type CapturePolicy int
+
+const (
+	// CaptureAll - hold all of the stream data in memory.  Capture.Limit is ignored.
+	CaptureAll CapturePolicy = iota
+	// CaptureHead - hold the first Capture.Limit bytes of the stream data
+	CaptureHead
+	// CaptureTail - hold the last Capture.Limit bytes of the stream data
+	CaptureTail
+	// CaptureHeadTail - hold the first and the last Capture.Limit / 2 bytes of the stream data
+	CaptureHeadTail
+	// CaptureSpill - hold the first Capture.Limit bytes of the stream data in memory and write all of the stream data to
+	// a temporary file once the limit is exceeded
+	CaptureSpill
+)
+
+// Capture is a struct that is defined with the capture limit of the standard output or standard error stream data of a
+// Command.  It includes the following data fields:
+//
+//     Capture.Policy - (CapturePolicy) data that is held when the limit is exceeded.  Default = CaptureAll
+//     Capture.Limit - (int64) maximum number of bytes that are held in memory.  A negative limit is a *StartError
+//     Capture.Dir - (string) directory of the CaptureSpill temporary file.  Default = os.TempDir()
+//
+// The Response reports the total number of bytes that the process wrote to the stream, whether the data in the Response
+// was truncated, and the path to the CaptureSpill temporary file.  The caller is responsible for removing the file.
+type Capture struct {
+	Policy CapturePolicy
+	Limit  int64
+	Dir    string
+}
+
+// captureBuffer holds the stream data of a process with the limits of a Capture.  It can be read while the process
+// writes to it.  The methods of a nil *captureBuffer are no-ops.
+type captureBuffer struct {
+	mu        sync.Mutex
+	capture   Capture
+	head      bytes.Buffer
+	tail      []byte
+	tailStart int
+	total     int64
+	truncated bool
+	file      *os.File
+	err       error
+}
+
+func newCaptureBuffer(capture Capture) *captureBuffer {
+	return &captureBuffer{capture: capture}
+}
+
+func (b *captureBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.total += int64(len(p))
+	limit := b.capture.Limit
+	switch b.capture.Policy {
+	case CaptureHead:
+		if len(b.writeHead(p, limit)) > 0 {
+			b.truncated = true
+		}
+	case CaptureTail:
+		b.writeTail(p, limit)
+	case CaptureHeadTail:
+		b.writeTail(b.writeHead(p, limit-limit/2), limit/2)
+	case CaptureSpill:
+		b.writeSpill(p, limit)
+	default:
+		b.head.Write(p)
+	}
+	// the process is never blocked by the capture limits
+	return len(p), nil
+}
+
+// writeHead writes the data to the head buffer up to the limit and returns the data that did not fit
+func (b *captureBuffer) writeHead(p []byte, limit int64) []byte {
+	n := limit - int64(b.head.Len())
+	if n < 0 {
+		n = 0
+	}
+	if int64(len(p)) <= n {
+		b.head.Write(p)
+		return nil
+	}
+	b.head.Write(p[:n])
+	return p[n:]
+}
+
+// writeTail writes the data to the tail ring buffer that holds the last limit bytes.  The oldest data is overwritten
+// in place once the buffer is full, so the cost of a write does not depend on the limit.
+func (b *captureBuffer) writeTail(p []byte, limit int64) {
+	if len(p) == 0 {
+		return
+	}
+	if int64(len(b.tail)+len(p)) > limit {
+		b.truncated = true
+	}
+	if int64(len(p)) >= limit {
+		// only the last limit bytes of the data are held
+		b.tail = append(b.tail[:0], p[int64(len(p))-limit:]...)
+		b.tailStart = 0
+		return
+	}
+	// fill the buffer before the oldest data is overwritten
+	if n := int(limit) - len(b.tail); n > 0 {
+		if n > len(p) {
+			n = len(p)
+		}
+		b.tail = append(b.tail, p[:n]...)
+		p = p[n:]
+	}
+	n := copy(b.tail[b.tailStart:], p)
+	copy(b.tail, p[n:])
+	b.tailStart = (b.tailStart + len(p)) % len(b.tail)
+}
+
+// writeSpill writes the data to the head buffer up to the limit.  The head buffer data and all of the following data
+// are written to a temporary file once the limit is exceeded.
+func (b *captureBuffer) writeSpill(p []byte, limit int64) {
+	if b.file == nil {
+		p = b.writeHead(p, limit)
+		if len(p) == 0 || b.err != nil {
+			return
+		}
+		b.truncated = true
+		b.file, b.err = os.CreateTemp(b.capture.Dir, "subprocess-*.log")
+		if b.err != nil {
+			return
+		}
+		_, b.err = b.file.Write(b.head.Bytes())
+	}
+	if b.err == nil {
+		_, b.err = b.file.Write(p)
+	}
+}
+
+// String returns the data that is held in the buffer
+func (b *captureBuffer) String() string {
+	if b == nil {
+		return ""
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.head.String() + string(b.tail[b.tailStart:]) + string(b.tail[:b.tailStart])
+}
+
+// close closes the CaptureSpill temporary file and returns the first error that was raised when it was written
+func (b *captureBuffer) close() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.file != nil {
+		if err := b.file.Close(); b.err == nil {
+			b.err = err
+		}
+	}
+	return b.err
+}
+
+// result returns the total number of bytes written to the buffer, whether the data was truncated, and the path to
+// the CaptureSpill temporary file
+func (b *captureBuffer) result() (int64, bool, string) {
+	if b == nil {
+		return 0, false, ""
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.file != nil {
+		return b.total, b.truncated, b.file.Name()
+	}
+	return b.total, b.truncated, ""
+}
@@ -0,0 +1,137 @@
+package subprocess
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCaptureBufferPolicies(t *testing.T) {
+	tests := []struct {
+		capture   Capture
+		expected  string
+		truncated bool
+	}{
+		{Capture{}, "0123456789abcdef", false},
+		{Capture{Policy: CaptureHead, Limit: 4}, "0123", true},
+		{Capture{Policy: CaptureHead, Limit: 32}, "0123456789abcdef", false},
+		{Capture{Policy: CaptureTail, Limit: 4}, "cdef", true},
+		{Capture{Policy: CaptureTail, Limit: 5}, "bcdef", true},
+		{Capture{Policy: CaptureTail, Limit: 10}, "6789abcdef", true},
+		{Capture{Policy: CaptureTail, Limit: 16}, "0123456789abcdef", false},
+		{Capture{Policy: CaptureTail, Limit: 0}, "", true},
+		{Capture{Policy: CaptureHeadTail, Limit: 6}, "012def", true},
+		{Capture{Policy: CaptureHeadTail, Limit: 9}, "01234cdef", true},
+		{Capture{Policy: CaptureHeadTail, Limit: 16}, "0123456789abcdef", false},
+	}
+	for _, test := range tests {
+		b := newCaptureBuffer(test.capture)
+		// write in chunks that cross the limits
+		for _, chunk := range []string{"012", "3456789", "a", "bcdef"} {
+			b.Write([]byte(chunk))
+		}
+		total, truncated, file := b.result()
+
+		if b.String() != test.expected {
+			t.Errorf("[FAIL] Expected capture %+v to hold '%s' and it held '%s'", test.capture, test.expected, b.String())
+		}
+		if total != 16 {
+			t.Errorf("[FAIL] Expected capture %+v to count 16 bytes and it counted %d", test.capture, total)
+		}
+		if truncated != test.truncated {
+			t.Errorf("[FAIL] Expected capture %+v truncated to be %t and it was %t", test.capture, test.truncated, truncated)
+		}
+		if file != "" {
+			t.Errorf("[FAIL] Expected capture %+v to not write a file and it wrote '%s'", test.capture, file)
+		}
+	}
+}
+
+func TestCaptureBufferSpill(t *testing.T) {
+	dir := t.TempDir()
+	b := newCaptureBuffer(Capture{Policy: CaptureSpill, Limit: 4, Dir: dir})
+	for _, chunk := range []string{"012", "3456789", "a", "bcdef"} {
+		b.Write([]byte(chunk))
+	}
+	if err := b.close(); err != nil {
+		t.Fatalf("[FAIL] Expected spill file to be written and instead it returned %v", err)
+	}
+	total, truncated, file := b.result()
+
+	if b.String() != "0123" {
+		t.Errorf("[FAIL] Expected spill capture to hold the first 4 bytes and it held '%s'", b.String())
+	}
+	if total != 16 || !truncated {
+		t.Errorf("[FAIL] Expected spill capture to count 16 truncated bytes and it counted %d (truncated %t)", total, truncated)
+	}
+	if filepath.Dir(file) != dir {
+		t.Errorf("[FAIL] Expected spill file to be written in '%s' and it was '%s'", dir, file)
+	}
+	data, _ := os.ReadFile(file)
+	if string(data) != "0123456789abcdef" {
+		t.Errorf("[FAIL] Expected spill file to hold all of the data and it held '%s'", data)
+	}
+}
+
+func TestCommandUnixCaptureTail(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		cmd := NewShellCommand("", "", "seq 1 10000; seq 1 10 >&2")
+		cmd.StdOutCapture = Capture{Policy: CaptureTail, Limit: 6}
+		cmd.StdErrCapture = Capture{Policy: CaptureHead, Limit: 1024}
+		response := cmd.Run(context.Background())
+
+		if response.StdOut != "10000\n" {
+			t.Errorf("[FAIL] Expected std out to hold the last line and it held '%s'", response.StdOut)
+		}
+		if !response.StdOutTruncated || response.StdOutBytes != 48894 {
+			t.Errorf("[FAIL] Expected std out to be truncated at 48894 bytes and it was %d bytes (truncated %t)", response.StdOutBytes, response.StdOutTruncated)
+		}
+		if response.StdErrTruncated || response.StdErrBytes != 21 {
+			t.Errorf("[FAIL] Expected std err to hold all 21 bytes and it was %d bytes (truncated %t)", response.StdErrBytes, response.StdErrTruncated)
+		}
+	}
+}
+
+func TestCommandUnixCaptureSpill(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		cmd := NewShellCommand("", "", "seq 1 10000")
+		cmd.StdOutCapture = Capture{Policy: CaptureSpill, Limit: 8, Dir: t.TempDir()}
+		response := cmd.Run(context.Background())
+		if response.StdOutFile == "" {
+			t.Fatalf("[FAIL] Expected std out to be written to a spill file")
+		}
+		data, err := os.ReadFile(response.StdOutFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if response.StdOut != "1\n2\n3\n4\n" {
+			t.Errorf("[FAIL] Expected std out to hold the first 8 bytes and it held '%s'", response.StdOut)
+		}
+		if int64(len(data)) != response.StdOutBytes || !strings.HasSuffix(string(data), "9999\n10000\n") {
+			t.Errorf("[FAIL] Expected spill file to hold all %d bytes and it held %d bytes", response.StdOutBytes, len(data))
+		}
+	}
+}
+
+func TestStreamDiscardCountsBytes(t *testing.T) {
+	response := RunStream(context.Background(), Stream{Discard: true}, "climock", "--stdout", "This is a test")
+
+	if response.StdOut != "" || response.StdOutBytes != 14 {
+		t.Errorf("[FAIL] Expected discarded std out to count 14 bytes and it held '%s' (%d bytes)", response.StdOut, response.StdOutBytes)
+	}
+}
+
+func TestCommandCaptureNegativeLimit(t *testing.T) {
+	for _, policy := range []CapturePolicy{CaptureHead, CaptureTail, CaptureHeadTail, CaptureSpill} {
+		cmd := NewCommand("climock", "--stdout", "This is a test")
+		cmd.StdErrCapture = Capture{Policy: policy, Limit: -1}
+		response := cmd.Run(context.Background())
+		if _, ok := response.Err.(*StartError); !ok || response.ExitCode != -1 {
+			t.Errorf("[FAIL] Expected a *StartError for a negative limit with policy %d, but received %#v", policy, response.Err)
+		}
+	}
+}
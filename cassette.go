@@ -0,0 +1,335 @@
+package subprocess
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unicode/utf8"
+)
+
+// Cassette is a struct that is defined with recorded command executions.  It is written to and read from a JSON file
+// by the RecordingRunner and the ReplayRunner.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a struct that is defined with one recorded command execution.  It includes the following data
+// fields:
+//
+//     Interaction.Path - (string) the executable of the command
+//     Interaction.Args - ([]string) the arguments of the command
+//     Interaction.Dir - (string) the working directory of the command
+//     Interaction.Env - (map[string]string) the environment variables of the command that were selected for recording
+//     Interaction.Stdin - (string) the standard input stream data of the command
+//     Interaction.StdOut - (string) the standard output stream data
+//     Interaction.StdErr - (string) the standard error stream data
+//     Interaction.ExitCode - (int) the exit status code
+//     Interaction.Signal - (int) the signal that terminated the process.  0 = none
+//     Interaction.Duration - (time.Duration) the wall-clock duration of the process in nanoseconds
+//     Interaction.Error - (string) the error of a process that could not be started
+//     Interaction.NotFound - (bool) the executable file could not be found
+//
+// The standard stream data is written to the cassette file as base64 (with "encoding": "base64") when it is not valid
+// UTF-8.
+type Interaction struct {
+	Path     string            `json:"path"`
+	Args     []string          `json:"args"`
+	Dir      string            `json:"dir,omitempty"`
+	Env      map[string]string `json:"env,omitempty"`
+	Stdin    string            `json:"stdin,omitempty"`
+	StdOut   string            `json:"stdout"`
+	StdErr   string            `json:"stderr"`
+	ExitCode int               `json:"exit_code"`
+	Signal   int               `json:"signal,omitempty"`
+	Duration time.Duration     `json:"duration"`
+	Error    string            `json:"error,omitempty"`
+	NotFound bool              `json:"not_found,omitempty"`
+}
+
+// MarshalJSON writes the Interaction with base64 standard stream data when the data is not valid UTF-8
+func (in Interaction) MarshalJSON() ([]byte, error) {
+	// the alias type drops the methods so that the encoding does not recurse
+	type interaction Interaction
+	if utf8.ValidString(in.Stdin) && utf8.ValidString(in.StdOut) && utf8.ValidString(in.StdErr) {
+		return json.Marshal(interaction(in))
+	}
+	encoded := in
+	encoded.Stdin = base64.StdEncoding.EncodeToString([]byte(in.Stdin))
+	encoded.StdOut = base64.StdEncoding.EncodeToString([]byte(in.StdOut))
+	encoded.StdErr = base64.StdEncoding.EncodeToString([]byte(in.StdErr))
+	return json.Marshal(struct {
+		interaction
+		Encoding string `json:"encoding"`
+	}{interaction(encoded), "base64"})
+}
+
+// UnmarshalJSON reads the Interaction and decodes the standard stream data with the "base64" encoding
+func (in *Interaction) UnmarshalJSON(data []byte) error {
+	type interaction Interaction
+	var decoded struct {
+		interaction
+		Encoding string `json:"encoding"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	switch decoded.Encoding {
+	case "":
+	case "base64":
+		for _, field := range []*string{&decoded.Stdin, &decoded.StdOut, &decoded.StdErr} {
+			value, err := base64.StdEncoding.DecodeString(*field)
+			if err != nil {
+				return fmt.Errorf("subprocess: invalid base64 stream data in the interaction: %w", err)
+			}
+			*field = string(value)
+		}
+	default:
+		return fmt.Errorf("subprocess: unknown interaction encoding %q", decoded.Encoding)
+	}
+	*in = Interaction(decoded.interaction)
+
+	return nil
+}
+
+// LoadCassette is a public function that reads a Cassette from a JSON file
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, err
+	}
+
+	return &cassette, nil
+}
+
+// Save writes the Cassette to a JSON file
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// RecordingRunner is a Runner that runs commands with another Runner and records each execution to a Cassette file.
+// Define a RecordingRunner with the public NewRecordingRunner function.  The cassette file is written after each
+// command.  Only the environment variables in RecordingRunner.EnvKeys are recorded so that secrets in the environment
+// are not written to the file.
+type RecordingRunner struct {
+	Runner  Runner
+	Path    string
+	EnvKeys []string
+
+	mu       sync.Mutex
+	cassette Cassette
+	err      error
+}
+
+// NewRecordingRunner is a public function that returns a *RecordingRunner that runs commands as system processes and
+// records them to the cassette file at path.
+// NewRecordingRunner takes the following parameters:
+//
+//  path (string) - path to the cassette file.  An existing file is replaced
+//  envKeys (...string) - names of the environment variables that are recorded
+//
+// Example:
+//
+//     func main() {
+//         recorder := NewRecordingRunner("testdata/terraform.json", "TF_WORKSPACE")
+//         plan(recorder) // runs the real terraform commands
+//         if err := recorder.Err(); err != nil {
+//             log.Fatal(err)
+//         }
+//     }
+func NewRecordingRunner(path string, envKeys ...string) *RecordingRunner {
+	return &RecordingRunner{Runner: DefaultRunner, Path: path, EnvKeys: envKeys}
+}
+
+// Run runs the command with RecordingRunner.Runner and records the execution.  Command.Stdin is read before the command
+// runs so that it can be recorded.
+func (r *RecordingRunner) Run(ctx context.Context, c *Command) Response {
+	interaction := Interaction{Path: c.Path, Args: slices.Clone(c.Args), Dir: c.Dir, Env: getEnvSubset(c, r.EnvKeys)}
+	// the standard input stream data is read once and passed to the command as bytes
+	rc := *c
+	if stdin, closeInput, err := c.Stdin.open(); err == nil && stdin != nil && !c.Stdin.pipe {
+		data, _ := io.ReadAll(stdin)
+		closeInput()
+		interaction.Stdin = string(data)
+		rc.Stdin = InputBytes(data)
+	}
+	res := r.Runner.Run(ctx, &rc)
+	interaction.StdOut = res.StdOut
+	interaction.StdErr = res.StdErr
+	interaction.ExitCode = res.ExitCode
+	interaction.Duration = res.Duration
+	if res.Signaled {
+		interaction.Signal = int(res.Signal)
+	}
+	var lookupErr *LookupError
+	var startErr *StartError
+	switch {
+	case errors.As(res.Err, &lookupErr):
+		interaction.Error = res.Err.Error()
+		interaction.NotFound = true
+	case errors.As(res.Err, &startErr):
+		interaction.Error = res.Err.Error()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	if err := r.cassette.Save(r.Path); err != nil && r.err == nil {
+		r.err = err
+	}
+
+	return res
+}
+
+// Err returns the first error that occurred when the cassette file was written
+func (r *RecordingRunner) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// ReplayRunner is a Runner that returns the recorded executions of a Cassette without starting system processes.
+// Define a ReplayRunner with the public NewReplayRunner function.  A command is matched with the first interaction
+// that was not replayed yet with the same executable, arguments, working directory, recorded environment variables,
+// and standard input stream data.  When all of the matching interactions were replayed the last one is repeated.  A
+// command that matches no interaction returns a Response with ExitCode -1 and an *UnexpectedCallError.
+type ReplayRunner struct {
+	mu       sync.Mutex
+	cassette *Cassette
+	replayed []bool
+}
+
+// NewReplayRunner is a public function that returns a *ReplayRunner for the cassette file at path.
+// NewReplayRunner takes the following parameters:
+//
+//  path (string) - path to the cassette file that was written by a RecordingRunner
+//
+// Example:
+//
+//     func TestPlan(t *testing.T) {
+//         replay, err := NewReplayRunner("testdata/terraform.json")
+//         if err != nil {
+//             t.Fatal(err)
+//         }
+//         if err := plan(replay); err != nil {
+//             t.Error(err)
+//         }
+//     }
+func NewReplayRunner(path string) (*ReplayRunner, error) {
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+	return &ReplayRunner{cassette: cassette, replayed: make([]bool, len(cassette.Interactions))}, nil
+}
+
+// Run returns the Response of the recorded interaction that matches the command
+func (r *ReplayRunner) Run(ctx context.Context, c *Command) Response {
+	var stdin string
+	if reader, closeInput, err := c.Stdin.open(); err == nil && reader != nil && !c.Stdin.pipe {
+		data, _ := io.ReadAll(reader)
+		closeInput()
+		stdin = string(data)
+	}
+	r.mu.Lock()
+	match := -1
+	for i, interaction := range r.cassette.Interactions {
+		if !interaction.matches(c, stdin) {
+			continue
+		}
+		match = i
+		if !r.replayed[i] {
+			break
+		}
+	}
+	if match >= 0 {
+		r.replayed[match] = true
+	}
+	r.mu.Unlock()
+	if match < 0 {
+		return Response{ExitCode: -1, Err: &UnexpectedCallError{Executable: c.Path, Args: slices.Clone(c.Args)}}
+	}
+
+	return streamResponse(c, r.cassette.Interactions[match].response())
+}
+
+// matches returns true when the interaction was recorded for the command and standard input stream data
+func (in Interaction) matches(c *Command, stdin string) bool {
+	if in.Path != c.Path || !slices.Equal(in.Args, c.Args) || in.Dir != c.Dir || in.Stdin != stdin {
+		return false
+	}
+	keys := make([]string, 0, len(in.Env))
+	for key := range in.Env {
+		keys = append(keys, key)
+	}
+	env := getEnvSubset(c, keys)
+	for key, value := range in.Env {
+		if env[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// response returns the Response of the recorded interaction
+func (in Interaction) response() Response {
+	res := Response{
+		StdOut:      in.StdOut,
+		StdErr:      in.StdErr,
+		ExitCode:    in.ExitCode,
+		Duration:    in.Duration,
+		StdOutBytes: int64(len(in.StdOut)),
+		StdErrBytes: int64(len(in.StdErr)),
+	}
+	switch {
+	case in.NotFound:
+		res.Err = &LookupError{Executable: in.Path, Err: errors.New(in.Error)}
+	case in.Error != "":
+		res.Err = &StartError{Executable: in.Path, Err: errors.New(in.Error)}
+	case in.Signal != 0:
+		res.Signaled = true
+		res.Signal = syscall.Signal(in.Signal)
+		res.Err = &SignalError{Signal: res.Signal}
+	case in.ExitCode != 0:
+		res.Err = &ExitError{ExitCode: in.ExitCode}
+	}
+
+	return res
+}
+
+// getEnvSubset returns the values of the environment variables of the command with the keys.  Variables that are not
+// defined are omitted.
+func getEnvSubset(c *Command, keys []string) map[string]string {
+	if len(keys) == 0 {
+		return nil
+	}
+	env := c.environ()
+	if env == nil {
+		env = os.Environ()
+	}
+	subset := make(map[string]string)
+	for _, kv := range env {
+		key, value, _ := strings.Cut(kv, "=")
+		if slices.Contains(keys, key) {
+			subset[key] = value
+		}
+	}
+
+	return subset
+}
@@ -0,0 +1,105 @@
+package subprocess
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		path := filepath.Join(t.TempDir(), "cassette.json")
+		recorder := NewRecordingRunner(path, "GREETING")
+		cmd := NewShellCommand("", "", `cat; echo "$GREETING"; echo oops >&2; exit 3`)
+		cmd.Setenv("GREETING", "hello")
+		cmd.Setenv("SECRET", "hunter2")
+		cmd.Stdin = InputString("input\n")
+		recorded := recorder.Run(context.Background(), cmd)
+		recorder.Run(context.Background(), NewCommand("bogusexecutable"))
+		if err := recorder.Err(); err != nil {
+			t.Fatalf("[FAIL] Expected the cassette to be written, but received error %v", err)
+		}
+		if recorded.StdOut != "input\nhello\n" || recorded.ExitCode != 3 {
+			t.Errorf("[FAIL] Expected the recording to run the command, but received '%s' (%d)", recorded.StdOut, recorded.ExitCode)
+		}
+
+		cassette, err := LoadCassette(path)
+		if err != nil || len(cassette.Interactions) != 2 {
+			t.Fatalf("[FAIL] Expected 2 recorded interactions, but received %v", err)
+		}
+		interaction := cassette.Interactions[0]
+		if interaction.Stdin != "input\n" || interaction.StdErr != "oops\n" || interaction.Duration <= 0 {
+			t.Errorf("[FAIL] Expected the recorded stdin, stderr, and duration, but received %+v", interaction)
+		}
+		if len(interaction.Env) != 1 || interaction.Env["GREETING"] != "hello" {
+			t.Errorf("[FAIL] Expected only the GREETING environment variable to be recorded, but received %v", interaction.Env)
+		}
+
+		replay, err := NewReplayRunner(path)
+		if err != nil {
+			t.Fatalf("[FAIL] Expected the cassette to load, but received error %v", err)
+		}
+		cmd.Stdin = InputString("input\n")
+		response := replay.Run(context.Background(), cmd)
+		var exitErr *ExitError
+		if response.StdOut != recorded.StdOut || response.StdErr != "oops\n" || !errors.As(response.Err, &exitErr) || exitErr.ExitCode != 3 {
+			t.Errorf("[FAIL] Expected the replayed Response to match the recording, but received %+v", response)
+		}
+		var lookupErr *LookupError
+		if response := replay.Run(context.Background(), NewCommand("bogusexecutable")); !errors.As(response.Err, &lookupErr) {
+			t.Errorf("[FAIL] Expected the replayed *LookupError, but received %v", response.Err)
+		}
+		cmd.Setenv("GREETING", "goodbye")
+		var callErr *UnexpectedCallError
+		if response := replay.Run(context.Background(), cmd); !errors.As(response.Err, &callErr) {
+			t.Errorf("[FAIL] Expected an *UnexpectedCallError for a different environment, but received %v", response.Err)
+		}
+	}
+}
+
+func TestReplayRunnerOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	cassette := Cassette{Interactions: []Interaction{
+		{Path: "git", Args: []string{"fetch"}, ExitCode: 128, StdErr: "network down\n"},
+		{Path: "git", Args: []string{"fetch"}, StdOut: "fetched\n"},
+	}}
+	if err := cassette.Save(path); err != nil {
+		t.Fatalf("[FAIL] Expected the cassette to be saved, but received error %v", err)
+	}
+	replay, _ := NewReplayRunner(path)
+	var codes []int
+	for i := 0; i < 3; i++ {
+		codes = append(codes, replay.Run(context.Background(), NewCommand("git", "fetch")).ExitCode)
+	}
+	if codes[0] != 128 || codes[1] != 0 || codes[2] != 0 {
+		t.Errorf("[FAIL] Expected the interactions in order and the last one repeated, but received %v", codes)
+	}
+}
+
+func TestCassetteBinaryStreams(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	binary := string([]byte{0xff, 0xfe, 0x00, 'o', 'k', 0x80})
+	cassette := Cassette{Interactions: []Interaction{
+		{Path: "cat", Stdin: binary, StdOut: binary, StdErr: "text"},
+		{Path: "echo", StdOut: "text\n"},
+	}}
+	if err := cassette.Save(path); err != nil {
+		t.Fatalf("[FAIL] Expected the cassette to be saved, but received %v", err)
+	}
+	loaded, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("[FAIL] Expected the cassette to be loaded, but received %v", err)
+	}
+	if len(loaded.Interactions) != 2 {
+		t.Fatalf("[FAIL] Expected 2 interactions, but received %d", len(loaded.Interactions))
+	}
+	first, second := loaded.Interactions[0], loaded.Interactions[1]
+	if first.Stdin != binary || first.StdOut != binary || first.StdErr != "text" || first.Path != "cat" {
+		t.Errorf("[FAIL] Expected the binary stream data to round-trip, but received %q, %q, %q", first.Stdin, first.StdOut, first.StdErr)
+	}
+	if second.StdOut != "text\n" || second.Path != "echo" {
+		t.Errorf("[FAIL] Expected the text stream data to round-trip, but received %q", second.StdOut)
+	}
+}
@@ -0,0 +1,25 @@
+package subprocess
+
+// Cgroup is a struct that is defined with the cgroup v2 configuration of a Command.  A transient cgroup is created for
+// each run of the command and the process is started in it so that the limits apply to the whole process tree.  The
+// cgroup and any processes that remain in it are removed when the command completes.  Cgroup is supported on Linux.
+// It includes the following data fields:
+//
+//     Cgroup.Parent - (string) path to the cgroup v2 directory that the transient cgroup is created in.  Required when a
+//                     limit is defined.  Default = the cgroup of the current process, only without limits
+//     Cgroup.MemoryMax - (int64) memory limit of the process tree in bytes (memory.max).  Default (0) = no limit
+//     Cgroup.CPUMax - (float64) CPU limit of the process tree in CPUs, e.g. 0.5 = half of one CPU (cpu.max).  Default
+//                     (0) = no limit
+//     Cgroup.PidsMax - (int64) maximum number of processes in the process tree (pids.max).  Default (0) = no limit
+//
+// cgroup v2 does not allow controllers to be enabled for the child cgroups of a cgroup that holds processes, so the
+// limits require a Parent cgroup without processes that is delegated to the current user (e.g. a cgroup that systemd
+// created with Delegate=yes).  The controllers for the limits are enabled in the cgroup.subtree_control file of the
+// Parent when they are not enabled yet.  Response.MemoryPeak and Response.OOMKilled report the memory accounting of
+// the cgroup.
+type Cgroup struct {
+	Parent    string
+	MemoryMax int64
+	CPUMax    float64
+	PidsMax   int64
+}
@@ -0,0 +1,219 @@
+package subprocess
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// cpuMaxPeriod is the cpu.max period in microseconds
+const cpuMaxPeriod = 100000
+
+// cgroup is a transient cgroup v2 directory that a Command process is started in
+type cgroup struct {
+	path string
+	dir  *os.File
+}
+
+// newCgroup creates a transient cgroup with the limits of the configuration and starts the command in it
+func newCgroup(cmd *exec.Cmd, config *Cgroup) (*cgroup, error) {
+	// the controllers for the limits are enabled in the parent cgroup
+	var controllers []string
+	if config.MemoryMax > 0 {
+		controllers = append(controllers, "memory")
+	}
+	if config.CPUMax > 0 {
+		controllers = append(controllers, "cpu")
+	}
+	if config.PidsMax > 0 {
+		controllers = append(controllers, "pids")
+	}
+	parent := config.Parent
+	if parent == "" {
+		// the controllers cannot be enabled for the child cgroups of a cgroup that holds processes, which includes the
+		// cgroup of the current process
+		if len(controllers) > 0 {
+			return nil, errors.New("subprocess: Cgroup.Parent must be defined with a delegated cgroup for the limits")
+		}
+		var err error
+		if parent, err = getCurrentCgroup(); err != nil {
+			return nil, err
+		}
+	}
+	if err := enableControllers(parent, controllers); err != nil {
+		return nil, err
+	}
+	path, err := os.MkdirTemp(parent, "subprocess-")
+	if err != nil {
+		return nil, fmt.Errorf("subprocess: unable to create a cgroup: %w", err)
+	}
+	g := &cgroup{path: path}
+	limits := map[string]string{}
+	if config.MemoryMax > 0 {
+		limits["memory.max"] = strconv.FormatInt(config.MemoryMax, 10)
+	}
+	if config.CPUMax > 0 {
+		limits["cpu.max"] = fmt.Sprintf("%d %d", int64(config.CPUMax*cpuMaxPeriod), cpuMaxPeriod)
+	}
+	if config.PidsMax > 0 {
+		limits["pids.max"] = strconv.FormatInt(config.PidsMax, 10)
+	}
+	for file, value := range limits {
+		if err := os.WriteFile(filepath.Join(path, file), []byte(value), 0); err != nil {
+			g.remove()
+			return nil, fmt.Errorf("subprocess: unable to set the cgroup limit %s: %w", file, err)
+		}
+	}
+	if g.dir, err = os.Open(path); err != nil {
+		g.remove()
+		return nil, err
+	}
+	// the process is created in the cgroup with clone3 so that it never runs outside of it
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = int(g.dir.Fd())
+
+	return g, nil
+}
+
+// enableControllers enables the controllers in the cgroup.subtree_control file of the parent cgroup when they are not
+// enabled yet
+func enableControllers(parent string, controllers []string) error {
+	if len(controllers) == 0 {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(parent, "cgroup.subtree_control"))
+	if err != nil {
+		return fmt.Errorf("subprocess: unable to read the cgroup controllers of %s: %w", parent, err)
+	}
+	enabled := strings.Fields(string(data))
+	var missing []string
+	for _, controller := range controllers {
+		if !slices.Contains(enabled, controller) {
+			missing = append(missing, "+"+controller)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	err = os.WriteFile(filepath.Join(parent, "cgroup.subtree_control"), []byte(strings.Join(missing, " ")), 0)
+	if errors.Is(err, syscall.EBUSY) {
+		return fmt.Errorf("subprocess: unable to enable the cgroup controllers in %s because it holds processes: %w",
+			parent, err)
+	}
+	if err != nil {
+		return fmt.Errorf("subprocess: unable to enable the cgroup controllers in %s: %w", parent, err)
+	}
+
+	return nil
+}
+
+// result defines the memory accounting fields of a subprocess.Response struct with the data of the cgroup
+func (g *cgroup) result(res *Response) {
+	if data, err := os.ReadFile(filepath.Join(g.path, "memory.peak")); err == nil {
+		res.MemoryPeak, _ = strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	}
+	f, err := os.Open(filepath.Join(g.path, "memory.events"))
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if key, value, ok := strings.Cut(scanner.Text(), " "); ok && key == "oom_kill" {
+			res.OOMKilled = value != "0"
+		}
+	}
+}
+
+// remove kills the processes that remain in the cgroup and removes it
+func (g *cgroup) remove() {
+	if g.dir != nil {
+		g.dir.Close()
+	}
+	// cgroup.kill is not available before Linux 5.14.  The processes are killed one by one then.
+	if os.WriteFile(filepath.Join(g.path, "cgroup.kill"), []byte("1"), 0) != nil {
+		for _, pid := range g.pids() {
+			syscall.Kill(pid, syscall.SIGKILL)
+		}
+	}
+	// the cgroup can only be removed after the killed processes have exited
+	for i := 0; i < 100; i++ {
+		err := os.Remove(g.path)
+		if err == nil || !errors.Is(err, syscall.EBUSY) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// pids returns the process IDs of the processes in the cgroup
+func (g *cgroup) pids() []int {
+	data, err := os.ReadFile(filepath.Join(g.path, "cgroup.procs"))
+	if err != nil {
+		return nil
+	}
+	var pids []int
+	for _, field := range strings.Fields(string(data)) {
+		if pid, err := strconv.Atoi(field); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+
+	return pids
+}
+
+// getCurrentCgroup returns the path to the cgroup v2 directory of the current process
+func getCurrentCgroup() (string, error) {
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return "", err
+	}
+	var current string
+	found := false
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(line, "0::"); ok {
+			current, found = rest, true
+		}
+	}
+	if !found {
+		return "", errors.New("subprocess: the current process is not in a cgroup v2 hierarchy")
+	}
+	mount, err := getCgroupMount()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(mount, current), nil
+}
+
+// getCgroupMount returns the mount point of the cgroup v2 file system
+func getCgroupMount() (string, error) {
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// the file system type follows the " - " separator
+		fields := strings.Fields(scanner.Text())
+		for i, field := range fields {
+			if field == "-" && i+1 < len(fields) && fields[i+1] == "cgroup2" && len(fields) > 4 {
+				return fields[4], nil
+			}
+		}
+	}
+
+	return "", errors.New("subprocess: cgroup v2 is not mounted")
+}
@@ -0,0 +1,140 @@
+package subprocess
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+// currentCgroup returns the cgroup of the test process and skips the test when cgroup v2 is not available or the
+// cgroup is not writable
+func currentCgroup(t *testing.T) string {
+	current, err := getCurrentCgroup()
+	if err != nil {
+		t.Skipf("cgroup v2 is not available: %v", err)
+	}
+	if err := syscall.Access(current, 2); err != nil { // W_OK
+		t.Skipf("the cgroup %s is not writable: %v", current, err)
+	}
+	return current
+}
+
+// delegatedCgroup returns a new cgroup without processes with the controllers available for its child cgroups.  The
+// test is skipped when the controllers cannot be delegated from the cgroup of the test process.
+func delegatedCgroup(t *testing.T, controllers ...string) string {
+	current := currentCgroup(t)
+	data, _ := os.ReadFile(filepath.Join(current, "cgroup.controllers"))
+	for _, controller := range controllers {
+		if !slices.Contains(strings.Fields(string(data)), controller) {
+			t.Skipf("the %s controller is not available in %s", controller, current)
+		}
+	}
+	// the controllers are only available in a child cgroup when they are enabled in the cgroup of the test process,
+	// which fails when it holds processes and is not the root cgroup
+	subtree := filepath.Join(current, "cgroup.subtree_control")
+	data, err := os.ReadFile(subtree)
+	if err != nil {
+		t.Skipf("the controllers of %s cannot be read: %v", current, err)
+	}
+	var disable []string
+	for _, controller := range controllers {
+		if !slices.Contains(strings.Fields(string(data)), controller) {
+			disable = append(disable, "-"+controller)
+		}
+	}
+	if err := enableControllers(current, controllers); err != nil {
+		t.Skipf("the controllers cannot be delegated: %v", err)
+	}
+	// the controllers that were enabled by the test are disabled again after the test cgroup is removed
+	t.Cleanup(func() {
+		if len(disable) > 0 {
+			if err := os.WriteFile(subtree, []byte(strings.Join(disable, " ")), 0); err != nil {
+				t.Errorf("[FAIL] Expected the controllers of %s to be restored, but received %v", current, err)
+			}
+		}
+	})
+	parent, err := os.MkdirTemp(current, "subprocess-test-")
+	if err != nil {
+		t.Fatalf("[FAIL] Expected a cgroup to be created in %s, but received %v", current, err)
+	}
+	t.Cleanup(func() { os.Remove(parent) })
+	return parent
+}
+
+// runCgroup runs the command in a transient cgroup and fails the test when the command could not be started
+func runCgroup(t *testing.T, cmd *Command) Response {
+	response := cmd.Run(context.Background())
+	var startErr *StartError
+	if errors.As(response.Err, &startErr) {
+		t.Fatalf("[FAIL] Expected the command to start in a cgroup, but received %v", response.Err)
+	}
+	return response
+}
+
+func TestCommandCgroup(t *testing.T) {
+	currentCgroup(t)
+	cmd := NewCommand("cat", "/proc/self/cgroup")
+	cmd.Cgroup = &Cgroup{}
+	response := runCgroup(t, cmd)
+	var path string
+	for _, line := range strings.Split(response.StdOut, "\n") {
+		if rest, ok := strings.CutPrefix(line, "0::"); ok {
+			path = rest
+		}
+	}
+	if !strings.Contains(path, "/subprocess-") {
+		t.Fatalf("[FAIL] Expected the process to run in a transient cgroup, but received '%s'", response.StdOut)
+	}
+	mount, _ := getCgroupMount()
+	if _, err := os.Stat(mount + path); !os.IsNotExist(err) {
+		t.Errorf("[FAIL] Expected the transient cgroup to be removed, but received %v", err)
+	}
+}
+
+func TestCommandCgroupKillsProcessTree(t *testing.T) {
+	currentCgroup(t)
+	cmd := NewShellCommand("", "", "sleep 30 >/dev/null 2>&1 & echo $!")
+	cmd.Cgroup = &Cgroup{}
+	response := runCgroup(t, cmd)
+	stat, err := os.ReadFile("/proc/" + strings.TrimSpace(response.StdOut) + "/stat")
+	// a killed process that was not reaped yet is a zombie
+	if err == nil && !strings.Contains(string(stat), ") Z ") {
+		t.Errorf("[FAIL] Expected the background process to be killed with the cgroup, but received '%s'", stat)
+	}
+}
+
+func TestCommandCgroupParent(t *testing.T) {
+	parent := delegatedCgroup(t)
+	cmd := NewCommand("cat", "/proc/self/cgroup")
+	cmd.Cgroup = &Cgroup{Parent: parent}
+	response := runCgroup(t, cmd)
+	mount, _ := getCgroupMount()
+	if !strings.Contains(response.StdOut, "0::"+strings.TrimPrefix(parent, mount)+"/subprocess-") {
+		t.Errorf("[FAIL] Expected the process to run in a cgroup in %s, but received '%s'", parent, response.StdOut)
+	}
+}
+
+func TestCommandCgroupLimitsRequireParent(t *testing.T) {
+	cmd := NewCommand("true")
+	cmd.Cgroup = &Cgroup{MemoryMax: 32 * 1024 * 1024}
+	response := cmd.Run(context.Background())
+	var startErr *StartError
+	if !errors.As(response.Err, &startErr) || !strings.Contains(response.Err.Error(), "Cgroup.Parent") {
+		t.Errorf("[FAIL] Expected a *StartError for limits without a Parent, but received %v", response.Err)
+	}
+}
+
+func TestCommandCgroupMemoryMax(t *testing.T) {
+	parent := delegatedCgroup(t, "memory", "pids")
+	cmd := NewShellCommand("", "", `x=$(head -c 100000000 /dev/zero | tr '\0' a)`)
+	cmd.Cgroup = &Cgroup{Parent: parent, MemoryMax: 32 * 1024 * 1024, PidsMax: 16}
+	response := runCgroup(t, cmd)
+	if !response.OOMKilled || response.MemoryPeak == 0 {
+		t.Errorf("[FAIL] Expected an OOM kill and the peak memory usage, but received %t and %d", response.OOMKilled, response.MemoryPeak)
+	}
+}
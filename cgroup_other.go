@@ -0,0 +1,20 @@
+//go:build !linux
+
+package subprocess
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// cgroup is a transient cgroup v2 directory.  cgroups are only supported on Linux.
+type cgroup struct{}
+
+// newCgroup returns an error because cgroups are only supported on Linux
+func newCgroup(cmd *exec.Cmd, config *Cgroup) (*cgroup, error) {
+	return nil, errors.New("subprocess: Cgroup is only supported on Linux")
+}
+
+func (g *cgroup) result(res *Response) {}
+
+func (g *cgroup) remove() {}
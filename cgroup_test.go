@@ -0,0 +1,19 @@
+package subprocess
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+)
+
+func TestCommandCgroupUnsupported(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		cmd := NewCommand("true")
+		cmd.Cgroup = &Cgroup{}
+		var startErr *StartError
+		if response := cmd.Run(context.Background()); !errors.As(response.Err, &startErr) {
+			t.Errorf("[FAIL] Expected a *StartError for Cgroup on %s, but received %v", runtime.GOOS, response.Err)
+		}
+	}
+}
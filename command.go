@@ -0,0 +1,430 @@
+package subprocess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Command is a struct that is defined with the configuration of a system command.  The public Run, RunShell, and
+// related functions are thin wrappers over a Command.  Define a Command with the public NewCommand and NewShellCommand
+// functions and modify the following data fields before the command is run:
+//
+//     Command.Path - (string) the executable for the command
+//     Command.Args - ([]string) the arguments to the executable
+//     Command.Dir - (string) working directory of the process.  Default = working directory of the current process
+//     Command.Env - ([]string) environment of the process in "key=value" format.  Default (nil) = environment of the
+//                   current process
+//     Command.Stdin - (Input) data for the standard input stream of the process.  Use InputPipe to write to the
+//                     standard input stream while the process runs
+//     Command.Stream - (Stream) handlers for the standard output and standard error stream data of the process
+//     Command.Timeout - (time.Duration) the process is killed when it runs for longer than this duration.  Default (0) =
+//                       no timeout
+//     Command.ProcessGroup - (bool) start the process in a new process group.  The whole process group is killed when
+//                            the command times out or is canceled (macOS/Linux)
+//     Command.Session - (bool) start the process in a new session (and process group) with the same kill behavior as
+//                       Command.ProcessGroup (macOS/Linux)
+//     Command.Pdeathsig - (syscall.Signal) signal that the process receives when the current process dies (Linux).
+//                         The signal is sent when the thread that started the process exits.
+//     Command.WaitDelay - (time.Duration) maximum time to wait for the standard output and standard error streams to
+//                         close after the process exits or is killed.  Default (0) = wait until the streams close
+//     Command.KillSignal - (syscall.Signal) signal that is sent first when the command times out or is canceled and a
+//                          grace period is defined.  Default = SIGTERM
+//     Command.GracePeriod - (time.Duration) time that the process has to exit after Command.KillSignal before it is
+//                           killed with SIGKILL.  Default (0) = kill with SIGKILL immediately
+//
+//     Command.StdOutCapture - (Capture) limits of the standard output stream data that is held in the Response
+//     Command.StdErrCapture - (Capture) limits of the standard error stream data that is held in the Response
+//
+//     Command.PTY - (*PTY) run the process with a pseudo-terminal (macOS/Linux).  Default (nil) = pipes
+//     Command.Limits - (map[Resource]uint64) resource limits of the process (Linux)
+//     Command.Cgroup - (*Cgroup) run the process tree in a transient cgroup v2 with memory, CPU, and process limits
+//                      (Linux).  Default (nil) = the cgroup of the current process
+//     Command.Sandbox - (*Sandbox) run the process in new user, mount, network, and PID namespaces (Linux)
+//     Command.User - (string) user name or user ID that the process runs as (macOS/Linux).  HOME and USER are defined
+//                    for the user unless they are edited with Setenv or Unsetenv.  Default = the user of the current
+//                    process
+//     Command.Group - (string) group name or group ID that the process runs as.  Requires Command.User.  Default =
+//                     the primary group of Command.User
+//     Command.Groups - ([]string) supplementary group names or group IDs of the process.  Requires Command.User.
+//                      Default (nil) = the groups of Command.User.  An empty slice clears the supplementary groups
+//
+//     Command.Hook - (Hook) receives an event when the process starts and when it finishes.  Default (nil) = the Hook
+//                    of SetDefaultHook
+//     Command.Redaction - (*Redaction) rules that remove secrets from the arguments and the environment in the Hook
+//                         events.  Default (nil) = DefaultRedaction
+//
+// Switching to another user requires root privileges (or the CAP_SETUID and CAP_SETGID capabilities).  A *StartError
+// that wraps os.ErrPermission is returned without them.
+//
+// Command.Limits are applied by a helper process that re-executes the current executable (/proc/self/exe), sets the
+// limits with the setrlimit system call, and then executes the command, so the command never runs without the limits.
+// The helper process runs the package initialization of the program up to the init function of this package, so the
+// init functions and package variables of the packages that are initialized before it (its dependencies and the
+// packages that precede it in the initialization order) also run in the helper process and must not have side effects
+// such as writing files or opening connections.  /proc/self/exe refers to the file that the program was started from,
+// so removing or replacing the executable file does not affect the helper process, but /proc must be mounted.
+//
+// Command.WaitDelay also bounds the grace period when it is defined because exec.Cmd kills the process when the wait
+// delay expires.  Windows does not support signals other than kill and always kills the process immediately.
+//
+// Use the Setenv and Unsetenv methods to add or remove individual environment variables from Command.Env (or from the
+// environment of the current process when Command.Env is nil).
+type Command struct {
+	Path    string
+	Args    []string
+	Dir     string
+	Env     []string
+	Stdin   Input
+	Stream  Stream
+	Timeout time.Duration
+
+	ProcessGroup bool
+	Session      bool
+	Pdeathsig    syscall.Signal
+	WaitDelay    time.Duration
+
+	KillSignal  syscall.Signal
+	GracePeriod time.Duration
+
+	StdOutCapture Capture
+	StdErrCapture Capture
+
+	PTY    *PTY
+	Limits map[Resource]uint64
+	Cgroup *Cgroup
+
+	Sandbox *Sandbox
+
+	User   string
+	Group  string
+	Groups []string
+
+	Hook      Hook
+	Redaction *Redaction
+
+	envEdits []envEdit
+	cmdLine  string
+}
+
+// envEdit is an environment variable that is added to (or removed from) the environment of a Command process
+type envEdit struct {
+	key   string
+	value string
+	unset bool
+}
+
+// NewCommand is a public function that returns a *Command for an executable with optional arguments.
+// NewCommand takes the following parameters:
+//
+//  executable (string) - the executable for the command
+//  args (...string) - one or more arguments to the executable as a comma-delimited list of parameters
+//
+// Example:
+//
+//     func main() {
+//         cmd := NewCommand("go", "build", "./...")
+//         cmd.Dir = "/path/to/module"
+//         cmd.Setenv("GOOS", "linux")
+//         cmd.Timeout = 5 * time.Minute
+//         response := cmd.Run(context.Background())
+//         fmt.Printf("%d\n", response.ExitCode)
+//     }
+func NewCommand(executable string, args ...string) *Command {
+	return &Command{Path: executable, Args: args}
+}
+
+// NewShellCommand is a public function that returns a *Command that executes a system command with a shell.  The
+// parameters are defined with the same defaults as the public RunShell function.
+// NewShellCommand takes the following parameters:
+//
+//  shell (string) - path to the shell.  Defaults = /bin/sh on Linux, macOS; cmd.exe on Windows
+//  shellflag (string) - flag to run executable file with shell. Default = `-c` (macOS/Linux); `/C` (Win)
+//  command (...string) - one or more executable commands, comma-delimited parameter format
+//
+// Example (macOS/Linux):
+//
+//     func main() {
+//         cmd := NewShellCommand("", "", "make", "test")
+//         cmd.Dir = "/path/to/project"
+//         response := cmd.Run(context.Background())
+//         fmt.Printf("%d\n", response.ExitCode)
+//     }
+func NewShellCommand(shell string, shellflag string, command ...string) *Command {
+	shell, shellflag = getShell(shell, shellflag)
+	// define the system executable call
+	shellExecString := strings.Join(command, " ")
+
+	return NewCommand(shell, shellflag, shellExecString)
+}
+
+// Setenv adds an environment variable to the environment of the process.  An existing variable with the same key is
+// replaced.
+func (c *Command) Setenv(key string, value string) {
+	c.envEdits = append(c.envEdits, envEdit{key: key, value: value})
+}
+
+// Unsetenv removes an environment variable from the environment of the process
+func (c *Command) Unsetenv(key string) {
+	c.envEdits = append(c.envEdits, envEdit{key: key, unset: true})
+}
+
+// Run executes the command and returns the standard output stream, standard error stream, and exit status code data in
+// a subprocess.Response struct.  The process is killed if the context is canceled, its deadline is exceeded, or the
+// Command.Timeout duration passes before the command completes on its own.
+func (c *Command) Run(ctx context.Context) Response {
+	p, err := c.Start(ctx)
+	if err != nil {
+		return getStartResponse(err)
+	}
+	res, _ := p.Wait()
+
+	return res
+}
+
+// getStartResponse returns the Response of a command that could not be started.  A context that was already canceled
+// or past its deadline when the command was started is reported with the TimedOut and Canceled fields.
+func getStartResponse(err error) Response {
+	return Response{
+		ExitCode: -1,
+		Err:      err,
+		TimedOut: errors.Is(err, context.DeadlineExceeded),
+		Canceled: errors.Is(err, context.Canceled),
+	}
+}
+
+// Start starts the command and returns a *Process handle without waiting for the command to complete.  The process is
+// killed with the same behavior as Run.  A *LookupError or *StartError is returned when the process could not be
+// started.
+func (c *Command) Start(ctx context.Context) (*Process, error) {
+	return c.start(ctx, nil, nil)
+}
+
+// start starts the command with the standard input stream connected to pipeIn and the standard output stream connected
+// to pipeOut.  Command.Stdin is used when pipeIn is nil and the standard output stream data is collected when pipeOut
+// is nil.
+func (c *Command) start(ctx context.Context, pipeIn *os.File, pipeOut *os.File) (*Process, error) {
+	hook := c.hook()
+	p, err := c.startProcess(ctx, pipeIn, pipeOut, hook)
+	// the process never ran.  Only the finish event is sent.
+	if err != nil && hook != nil {
+		hook.Finished(ctx, FinishEvent{StartEvent: c.startEvent(0, time.Time{}), ExitCode: -1, Err: err})
+	}
+
+	return p, err
+}
+
+// startProcess starts the command with the behavior of start and sends the start event of the process to the hook
+func (c *Command) startProcess(ctx context.Context, pipeIn *os.File, pipeOut *os.File, hook Hook) (*Process, error) {
+	// the start error of a missing working directory names the executable file instead of the directory
+	if c.Dir != "" {
+		if info, err := os.Stat(c.Dir); err != nil {
+			return nil, &StartError{Executable: c.Path, Err: fmt.Errorf("subprocess: invalid working directory: %w", err)}
+		} else if !info.IsDir() {
+			return nil, &StartError{Executable: c.Path, Err: fmt.Errorf("subprocess: working directory %s is not a directory", c.Dir)}
+		}
+	}
+	if c.StdOutCapture.Limit < 0 || c.StdErrCapture.Limit < 0 {
+		return nil, &StartError{Executable: c.Path, Err: errors.New("subprocess: the Capture.Limit must not be negative")}
+	}
+	var cleanup []func()
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		cleanup = append(cleanup, cancel)
+	}
+	// define the system executable call
+	cmd := exec.CommandContext(ctx, c.Path, c.Args...)
+	cmd.Dir = c.Dir
+	cmd.Env = c.environ()
+	p := newProcess(ctx, cmd, c.Stream, c.StdOutCapture, c.StdErrCapture)
+	p.cleanup = cleanup
+	p.group = c.newGroup()
+	if err := setProcAttr(cmd, c); err != nil {
+		p.release()
+		return nil, &StartError{Executable: c.Path, Err: err}
+	}
+	if len(c.Limits) > 0 && !limitsSupported {
+		p.release()
+		return nil, &StartError{Executable: c.Path, Err: errors.New("subprocess: Limits is only supported on Linux")}
+	}
+	p.limits = c.Limits
+	if c.Sandbox != nil {
+		if err := setSandbox(cmd.SysProcAttr, c.Sandbox); err != nil {
+			p.release()
+			return nil, &StartError{Executable: c.Path, Err: err}
+		}
+	}
+	if c.User == "" && (c.Group != "" || c.Groups != nil) {
+		p.release()
+		return nil, &StartError{Executable: c.Path, Err: errors.New("subprocess: Group and Groups require User")}
+	}
+	if c.User != "" {
+		cred, err := lookupCredential(c.User, c.Group, c.Groups)
+		if err == nil {
+			err = setCredential(cmd.SysProcAttr, cred)
+		}
+		if err != nil {
+			p.release()
+			return nil, &StartError{Executable: c.Path, Err: err}
+		}
+		// the Setenv and Unsetenv edits take precedence over the variables of the user
+		env := setEnv(setEnv(c.Env, "HOME", cred.home), "USER", cred.username)
+		cmd.Env = c.editEnv(env)
+	}
+	if c.Cgroup != nil {
+		g, err := newCgroup(cmd, c.Cgroup)
+		if err != nil {
+			p.release()
+			return nil, &StartError{Executable: c.Path, Err: err}
+		}
+		p.cgroup = g
+		p.cleanup = append(p.cleanup, g.remove)
+	}
+	// signal the process (or the whole process group) with the termination policy when the context is done
+	p.term = newTerminator(cmd, c)
+	cmd.Cancel = p.term.cancel
+	cmd.WaitDelay = c.WaitDelay
+	stdin, closeInput, err := c.Stdin.open()
+	if err != nil {
+		p.release()
+		return nil, &StartError{Executable: c.Path, Err: err}
+	}
+	p.cleanup = append(p.cleanup, closeInput)
+	var pipeStdin *os.File
+	if c.Stdin.pipe {
+		// the write end of the pipe is returned by Process.Stdin
+		if pipeStdin, p.stdin, err = os.Pipe(); err != nil {
+			p.release()
+			return nil, &StartError{Executable: c.Path, Err: err}
+		}
+		p.cleanup = append(p.cleanup, func() {
+			pipeStdin.Close()
+			p.stdin.Close()
+		})
+		stdin = pipeStdin
+	}
+	cmd.Stdin = stdin
+	// the pipes are passed to the process directly without a copy in the current process
+	if pipeIn != nil {
+		cmd.Stdin = pipeIn
+	}
+	if pipeOut != nil {
+		cmd.Stdout = pipeOut
+	}
+	if c.PTY != nil {
+		if pipeIn != nil || pipeOut != nil {
+			p.release()
+			return nil, &StartError{Executable: c.Path, Err: errors.New("subprocess: PTY is not supported in a Pipeline")}
+		}
+		if err := p.setPTY(cmd, c.PTY, stdin); err != nil {
+			p.release()
+			return nil, &StartError{Executable: c.Path, Err: err}
+		}
+	}
+	var limitsStatus *os.File
+	if len(c.Limits) > 0 {
+		if limitsStatus, err = setLimitsHelper(cmd, c.Limits); err != nil {
+			p.startPTY(false)
+			p.release()
+			return nil, &StartError{Executable: c.Path, Err: err}
+		}
+	}
+	// start the system command.  The process never ran when this fails.
+	p.res.StartTime = time.Now()
+	if err = cmd.Start(); err != nil {
+		if c.Sandbox != nil {
+			err = getSandboxError(err)
+		}
+		if c.User != "" {
+			err = getCredentialError(err, c.User)
+		}
+		err = getStartError(c.Path, c.Dir, err)
+	}
+	// the helper process exits without running the command when the limits cannot be applied
+	if limitErr := waitLimitsHelper(cmd, limitsStatus, err == nil); limitErr != nil {
+		cmd.Wait()
+		err = &StartError{Executable: c.Path, Err: limitErr}
+	}
+	p.startPTY(err == nil)
+	if err != nil {
+		p.release()
+		return nil, err
+	}
+	// the process holds the read end of the InputPipe.  A pseudo-terminal copies the data in the current process.
+	if pipeStdin != nil && c.PTY == nil {
+		pipeStdin.Close()
+	}
+	if hook != nil {
+		p.hook = hook
+		p.event = c.startEvent(cmd.Process.Pid, p.res.StartTime)
+		hook.Started(ctx, p.event)
+	}
+	go p.wait()
+
+	return p, nil
+}
+
+// newGroup returns true when the process is started as the leader of a new process group
+func (c *Command) newGroup() bool {
+	return c.ProcessGroup || c.Session || c.PTY != nil
+}
+
+// environ returns the environment of the process with the Setenv and Unsetenv edits applied.  nil is returned when the
+// process inherits the environment of the current process without edits.
+func (c *Command) environ() []string {
+	if len(c.envEdits) == 0 {
+		return c.Env
+	}
+	env := c.Env
+	if env == nil {
+		env = os.Environ()
+	}
+
+	return c.editEnv(env)
+}
+
+// editEnv returns the environment with the Setenv and Unsetenv edits applied
+func (c *Command) editEnv(env []string) []string {
+	for _, edit := range c.envEdits {
+		env = removeEnv(env, edit.key)
+		if !edit.unset {
+			env = append(env, edit.key+"="+edit.value)
+		}
+	}
+
+	return env
+}
+
+// setEnv returns the environment with the variable defined.  The environment of the current process is used when env
+// is nil.
+func setEnv(env []string, key string, value string) []string {
+	if env == nil {
+		env = os.Environ()
+	}
+	return append(removeEnv(env, key), key+"="+value)
+}
+
+// removeEnv returns a copy of the environment without the variables that are defined with key.  Keys are case
+// insensitive on Windows.
+func removeEnv(env []string, key string) []string {
+	result := make([]string, 0, len(env))
+	for _, kv := range env {
+		k := kv
+		if i := strings.Index(kv, "="); i >= 0 {
+			k = kv[:i]
+		}
+		if k == key || (runtime.GOOS == "windows" && strings.EqualFold(k, key)) {
+			continue
+		}
+		result = append(result, kv)
+	}
+
+	return result
+}
@@ -0,0 +1,242 @@
+package subprocess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestCommandCliMockStdoutZero(t *testing.T) {
+	response := NewCommand("climock", "--stdout", "This is a test").Run(context.Background())
+
+	if response.ExitCode != 0 {
+		t.Errorf("[FAIL] Expected mock exit code to be zero and it was %d", response.ExitCode)
+	}
+	if response.StdOut != "This is a test" {
+		t.Errorf("[FAIL] Expected mock std out to be 'This is a test' and it was actually '%s'", response.StdOut)
+	}
+}
+
+func TestCommandUnixDir(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		dir, err := filepath.EvalSymlinks(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmd := NewCommand("pwd")
+		cmd.Dir = dir
+		response := cmd.Run(context.Background())
+
+		if strings.TrimSpace(response.StdOut) != dir {
+			t.Errorf("[FAIL] Expected working directory to be '%s' and it was actually '%s'", dir, response.StdOut)
+		}
+	}
+}
+
+func TestCommandUnixMissingDir(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		cmd := NewCommand("pwd")
+		cmd.Dir = "/bogus/directory"
+		response := cmd.Run(context.Background())
+
+		if _, ok := response.Err.(*StartError); !ok {
+			t.Errorf("[FAIL] Expected missing working directory to return a *StartError and instead it returned %#v", response.Err)
+		}
+		if !strings.Contains(response.Err.Error(), "/bogus/directory") || !errors.Is(response.Err, os.ErrNotExist) {
+			t.Errorf("[FAIL] Expected the error to name the missing working directory and instead it returned %v", response.Err)
+		}
+		if response.ExitCode != -1 {
+			t.Errorf("[FAIL] Expected missing working directory to return -1 exit status code and instead it returned %d", response.ExitCode)
+		}
+	}
+}
+
+func TestCommandUnixRelativeExecutableInDir(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		dir := t.TempDir()
+		// the executable file exists in the working directory but its interpreter does not
+		if err := os.WriteFile(filepath.Join(dir, "script"), []byte("#!/bogus/interpreter\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		cmd := NewCommand("./script")
+		cmd.Dir = dir
+		response := cmd.Run(context.Background())
+		if _, ok := response.Err.(*StartError); !ok {
+			t.Errorf("[FAIL] Expected an executable with a missing interpreter to return a *StartError and instead it returned %#v", response.Err)
+		}
+		cmd = NewCommand("./missing")
+		cmd.Dir = dir
+		response = cmd.Run(context.Background())
+		if _, ok := response.Err.(*LookupError); !ok {
+			t.Errorf("[FAIL] Expected a missing executable in the working directory to return a *LookupError and instead it returned %#v", response.Err)
+		}
+	}
+}
+
+func TestCommandUnixSetenvUnsetenv(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		os.Setenv("SUBPROCESS_TEST_UNSET", "inherited")
+		defer os.Unsetenv("SUBPROCESS_TEST_UNSET")
+		cmd := NewShellCommand("", "", "echo $SUBPROCESS_TEST_SET:$SUBPROCESS_TEST_UNSET")
+		cmd.Setenv("SUBPROCESS_TEST_SET", "first")
+		cmd.Setenv("SUBPROCESS_TEST_SET", "added")
+		cmd.Unsetenv("SUBPROCESS_TEST_UNSET")
+		response := cmd.Run(context.Background())
+
+		if response.StdOut != "added:\n" {
+			t.Errorf("[FAIL] Expected environment edits to print 'added:' and it was actually '%s'", response.StdOut)
+		}
+	}
+}
+
+func TestCommandUnixEnvReplace(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		cmd := NewCommand("/usr/bin/env")
+		cmd.Env = []string{"ONLY=this"}
+		response := cmd.Run(context.Background())
+
+		if response.StdOut != "ONLY=this\n" {
+			t.Errorf("[FAIL] Expected replaced environment to be 'ONLY=this' and it was actually '%s'", response.StdOut)
+		}
+	}
+}
+
+func TestCommandUnixTimeout(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		cmd := NewCommand("sleep", "10")
+		cmd.Timeout = 100 * time.Millisecond
+		response := cmd.Run(context.Background())
+
+		if !response.TimedOut {
+			t.Errorf("[FAIL] Expected TimedOut to be true for a command that exceeded the timeout")
+		}
+	}
+}
+
+func TestCommandUnixStdinReuse(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		cmd := NewCommand("cat")
+		cmd.Stdin = InputString("This is a test")
+		for i := 0; i < 2; i++ {
+			response := cmd.Run(context.Background())
+			if response.StdOut != "This is a test" {
+				t.Errorf("[FAIL] Expected run %d std out to be 'This is a test' and it was actually '%s'", i, response.StdOut)
+			}
+		}
+	}
+}
+
+func TestCommandUnixProcessGroupTimeout(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		// the grandchild sleep process holds the standard output stream open after the shell is killed
+		cmd := NewShellCommand("", "", "sleep 10 & sleep 10")
+		cmd.ProcessGroup = true
+		cmd.Timeout = 100 * time.Millisecond
+		start := time.Now()
+		response := cmd.Run(context.Background())
+
+		if time.Since(start) > 5*time.Second {
+			t.Errorf("[FAIL] Expected process group to be killed at the timeout and it ran for %v", time.Since(start))
+		}
+		if !response.TimedOut {
+			t.Errorf("[FAIL] Expected TimedOut to be true for a command that exceeded the timeout")
+		}
+	}
+}
+
+func TestCommandUnixSessionTimeout(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		cmd := NewShellCommand("", "", "sleep 10 & sleep 10")
+		cmd.Session = true
+		cmd.Timeout = 100 * time.Millisecond
+		start := time.Now()
+		response := cmd.Run(context.Background())
+
+		if time.Since(start) > 5*time.Second {
+			t.Errorf("[FAIL] Expected session process group to be killed at the timeout and it ran for %v", time.Since(start))
+		}
+		if !response.Signaled || response.Signal != syscall.SIGKILL {
+			t.Errorf("[FAIL] Expected shell to be killed by SIGKILL and it was %s", response)
+		}
+	}
+}
+
+func TestCommandUnixWaitDelay(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		// the shell exits while the background sleep process holds the standard output stream open
+		cmd := NewShellCommand("", "", "sleep 10 &")
+		cmd.WaitDelay = 100 * time.Millisecond
+		start := time.Now()
+		response := cmd.Run(context.Background())
+
+		if time.Since(start) > 5*time.Second {
+			t.Errorf("[FAIL] Expected command to return after the wait delay and it ran for %v", time.Since(start))
+		}
+		if response.ExitCode != 0 {
+			t.Errorf("[FAIL] Expected shell to return 0 exit status code and instead it returned %d", response.ExitCode)
+		}
+		if !errors.Is(response.Err, exec.ErrWaitDelay) {
+			t.Errorf("[FAIL] Expected open streams to return exec.ErrWaitDelay and instead it returned %v", response.Err)
+		}
+	}
+}
+
+// pdeathsigParentEnv is the environment variable that runs the test binary as the parent process of
+// TestCommandLinuxPdeathsig
+const pdeathsigParentEnv = "SUBPROCESS_TEST_PDEATHSIG_PARENT"
+
+// pdeathsigParent starts a long-running child process with Command.Pdeathsig, writes its process ID to the standard
+// output stream, and exits without waiting for it
+func pdeathsigParent() {
+	cmd := NewCommand("sleep", "30")
+	cmd.Pdeathsig = syscall.SIGKILL
+	p, err := cmd.Start(context.Background())
+	if err != nil {
+		os.Exit(1)
+	}
+	fmt.Println(p.PID())
+	os.Exit(0)
+}
+
+// processAlive returns true when the process exists and is not a zombie
+func processAlive(pid int) bool {
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return false
+	}
+	// the state follows the parenthesized command name
+	fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+	return len(fields) > 0 && fields[0] != "Z" && fields[0] != "X"
+}
+
+func TestCommandLinuxPdeathsig(t *testing.T) {
+	if runtime.GOOS == "linux" {
+		cmd := NewCommand(os.Args[0], "-test.run=^$")
+		cmd.Setenv(pdeathsigParentEnv, "1")
+		response := cmd.Run(context.Background())
+		pid, err := strconv.Atoi(strings.TrimSpace(response.StdOut))
+		if err != nil || response.ExitCode != 0 {
+			t.Fatalf("[FAIL] Expected the parent process to start a child, but received %q (%v)", response.StdOut, response.Err)
+		}
+
+		deadline := time.Now().Add(5 * time.Second)
+		for processAlive(pid) && time.Now().Before(deadline) {
+			time.Sleep(20 * time.Millisecond)
+		}
+		if processAlive(pid) {
+			if process, err := os.FindProcess(pid); err == nil {
+				process.Kill()
+			}
+			t.Errorf("[FAIL] Expected the child process to be killed when its parent process exited")
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package subprocess
+
+import (
+	"fmt"
+	"os/user"
+	"strconv"
+)
+
+// credential is the resolved user, group, and supplementary groups of a Command process
+type credential struct {
+	uid      uint32
+	gid      uint32
+	groups   []uint32
+	username string
+	home     string
+}
+
+// lookupCredential resolves the user name or ID, the group name or ID, and the supplementary group names or IDs of a
+// Command through os/user.  The primary group and the supplementary groups of the user are used when the group is
+// empty and groups is nil.
+func lookupCredential(userName string, groupName string, groups []string) (*credential, error) {
+	u, err := user.Lookup(userName)
+	if err != nil {
+		if _, numErr := strconv.ParseUint(userName, 10, 32); numErr != nil {
+			return nil, fmt.Errorf("subprocess: unknown user %q: %w", userName, err)
+		}
+		if u, err = user.LookupId(userName); err != nil {
+			return nil, fmt.Errorf("subprocess: unknown user %q: %w", userName, err)
+		}
+	}
+	cred := &credential{username: u.Username, home: u.HomeDir}
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("subprocess: invalid user ID %q: %w", u.Uid, err)
+	}
+	cred.uid = uint32(uid)
+	if groupName == "" {
+		groupName = u.Gid
+	}
+	if cred.gid, err = lookupGroupID(groupName); err != nil {
+		return nil, err
+	}
+	if groups == nil {
+		if groups, err = u.GroupIds(); err != nil {
+			return nil, fmt.Errorf("subprocess: unable to look up the groups of user %q: %w", userName, err)
+		}
+	}
+	for _, group := range groups {
+		gid, err := lookupGroupID(group)
+		if err != nil {
+			return nil, err
+		}
+		cred.groups = append(cred.groups, gid)
+	}
+
+	return cred, nil
+}
+
+// lookupGroupID returns the ID of a group name.  Numeric group IDs are used as they are.
+func lookupGroupID(group string) (uint32, error) {
+	if gid, err := strconv.ParseUint(group, 10, 32); err == nil {
+		return uint32(gid), nil
+	}
+	g, err := user.LookupGroup(group)
+	if err != nil {
+		return 0, fmt.Errorf("subprocess: unknown group %q: %w", group, err)
+	}
+	gid, err := strconv.ParseUint(g.Gid, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("subprocess: invalid group ID %q: %w", g.Gid, err)
+	}
+
+	return uint32(gid), nil
+}
@@ -0,0 +1,70 @@
+package subprocess
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/user"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCommandUser(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Getuid() != 0 {
+		t.Skip("switching users requires root privileges on macOS/Linux")
+	}
+	nobody, err := user.Lookup("nobody")
+	if err != nil {
+		t.Skip("the nobody user is not defined")
+	}
+	cmd := NewShellCommand("", "", `id -u; id -g; id -G; echo "$HOME $USER"`)
+	cmd.User = "nobody"
+	cmd.Groups = []string{}
+	response := cmd.Run(context.Background())
+	expected := nobody.Uid + "\n" + nobody.Gid + "\n" + nobody.Gid + "\n" + nobody.HomeDir + " nobody\n"
+	if response.StdOut != expected {
+		t.Errorf("[FAIL] Expected '%q' for the nobody user, but received '%q' (%v)", expected, response.StdOut, response.Err)
+	}
+	cmd = NewCommand("id", "-u")
+	cmd.User = nobody.Uid
+	cmd.Group = "0"
+	cmd.Setenv("HOME", "/tmp")
+	response = cmd.Run(context.Background())
+	if strings.TrimSpace(response.StdOut) != nobody.Uid {
+		t.Errorf("[FAIL] Expected the numeric user ID %s, but received '%s' (%v)", nobody.Uid, response.StdOut, response.Err)
+	}
+}
+
+func TestCommandUserErrors(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		cmd := NewCommand("true")
+		cmd.User = "bogususer"
+		response := cmd.Run(context.Background())
+		var startErr *StartError
+		if !errors.As(response.Err, &startErr) || !strings.Contains(response.Err.Error(), `unknown user "bogususer"`) {
+			t.Errorf("[FAIL] Expected a *StartError for an unknown user, but received %v", response.Err)
+		}
+		cmd = NewCommand("true")
+		cmd.User = "root"
+		cmd.Group = "bogusgroup"
+		response = cmd.Run(context.Background())
+		if !errors.As(response.Err, &startErr) || !strings.Contains(response.Err.Error(), `unknown group "bogusgroup"`) {
+			t.Errorf("[FAIL] Expected a *StartError for an unknown group, but received %v", response.Err)
+		}
+		cmd = NewCommand("true")
+		cmd.Groups = []string{"0"}
+		response = cmd.Run(context.Background())
+		if !errors.As(response.Err, &startErr) || !strings.Contains(response.Err.Error(), "require User") {
+			t.Errorf("[FAIL] Expected a *StartError for Groups without User, but received %v", response.Err)
+		}
+		if os.Getuid() != 0 {
+			cmd = NewCommand("true")
+			cmd.User = "root"
+			response = cmd.Run(context.Background())
+			if !errors.Is(response.Err, os.ErrPermission) {
+				t.Errorf("[FAIL] Expected a permission error without root privileges, but received %v", response.Err)
+			}
+		}
+	}
+}
@@ -0,0 +1,25 @@
+//go:build unix
+
+package subprocess
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+// setCredential defines the user, group, and supplementary groups that the process runs as
+func setCredential(attr *syscall.SysProcAttr, cred *credential) error {
+	attr.Credential = &syscall.Credential{Uid: cred.uid, Gid: cred.gid, Groups: cred.groups}
+	return nil
+}
+
+// getCredentialError returns the error for a process that could not switch to the user.  The error wraps
+// os.ErrPermission when the current process lacks the privileges.
+func getCredentialError(err error, userName string) error {
+	if errors.Is(err, syscall.EPERM) {
+		return fmt.Errorf("subprocess: not permitted to run as user %q (root or the CAP_SETUID and CAP_SETGID "+
+			"capabilities are required): %w", userName, err)
+	}
+	return err
+}
@@ -0,0 +1,15 @@
+package subprocess
+
+import (
+	"errors"
+	"syscall"
+)
+
+// setCredential returns an error because a process cannot be started as another user on Windows
+func setCredential(attr *syscall.SysProcAttr, cred *credential) error {
+	return errors.New("subprocess: User is not supported on Windows")
+}
+
+func getCredentialError(err error, userName string) error {
+	return err
+}
@@ -0,0 +1,125 @@
+package subprocess
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+)
+
+// LookupError is the error type that is defined in Response.Err when the executable file could not be found on the
+// system PATH or at the defined path.  The process was never started.
+type LookupError struct {
+	Executable string
+	Err        error
+}
+
+func (e *LookupError) Error() string {
+	return fmt.Sprintf("subprocess: executable %q not found: %v", e.Executable, e.Err)
+}
+
+// Unwrap returns the underlying error raised by the executable file lookup
+func (e *LookupError) Unwrap() error {
+	return e.Err
+}
+
+// StartError is the error type that is defined in Response.Err when the executable file was found but the process
+// could not be started (e.g. permission denied, invalid working directory).  The process was never started.
+type StartError struct {
+	Executable string
+	Err        error
+}
+
+func (e *StartError) Error() string {
+	return fmt.Sprintf("subprocess: unable to start %q: %v", e.Executable, e.Err)
+}
+
+// Unwrap returns the underlying error raised by the process start
+func (e *StartError) Unwrap() error {
+	return e.Err
+}
+
+// ExitError is the error type that is defined in Response.Err when the process exited on its own with a non-zero exit
+// status code.
+type ExitError struct {
+	ExitCode int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("subprocess: exit status %d", e.ExitCode)
+}
+
+// SignalError is the error type that is defined in Response.Err when the process was terminated by a signal.
+type SignalError struct {
+	Signal syscall.Signal
+}
+
+func (e *SignalError) Error() string {
+	return fmt.Sprintf("subprocess: terminated by signal %s", SignalName(e.Signal))
+}
+
+// ExpectError is the error type that is returned by the Session.Expect methods when the pattern was not matched before
+// the timeout passed (Timeout = true) or before the process exited and closed its output streams (EOF = true).  Output
+// holds the unmatched output of the process.
+type ExpectError struct {
+	Pattern string
+	Output  string
+	Timeout bool
+	EOF     bool
+}
+
+func (e *ExpectError) Error() string {
+	if e.EOF {
+		return fmt.Sprintf("subprocess: process exited before %q was matched", e.Pattern)
+	}
+	return fmt.Sprintf("subprocess: timed out waiting for %q", e.Pattern)
+}
+
+// UnexpectedCallError is the error type that is defined in Response.Err when a FakeRunner runs a command that does not
+// match one of its expectations.  The process was never started.
+type UnexpectedCallError struct {
+	Executable string
+	Args       []string
+}
+
+func (e *UnexpectedCallError) Error() string {
+	return fmt.Sprintf("subprocess: unexpected call to %q with arguments %q", e.Executable, e.Args)
+}
+
+// getStartError returns a *LookupError when the executable file could not be found and a *StartError for all other
+// errors that are raised when a process is started.  A relative executable path is resolved in the working directory.
+func getStartError(executable string, dir string, err error) error {
+	var execError *exec.Error
+	if errors.As(err, &execError) {
+		return &LookupError{Executable: executable, Err: err}
+	}
+	// the working directory exists when the process is started, so a missing path is the executable file.  A file that
+	// exists can still fail with the same error when its interpreter is missing.
+	var pathError *os.PathError
+	if errors.As(err, &pathError) && errors.Is(err, os.ErrNotExist) {
+		path := pathError.Path
+		if !filepath.IsAbs(path) && dir != "" {
+			path = filepath.Join(dir, path)
+		}
+		if _, statErr := os.Stat(path); statErr != nil {
+			return &LookupError{Executable: executable, Err: err}
+		}
+	}
+	return &StartError{Executable: executable, Err: err}
+}
+
+// getWaitError returns a *SignalError or an *ExitError for a process that completed with a non-zero exit status code.
+// Errors that are not raised by the process exit status are returned unmodified.
+func getWaitError(err error) error {
+	var exitError *exec.ExitError
+	if !errors.As(err, &exitError) {
+		return err
+	}
+	status := exitError.Sys().(syscall.WaitStatus)
+	if status.Signaled() {
+		return &SignalError{Signal: status.Signal()}
+	}
+	return &ExitError{ExitCode: status.ExitStatus()}
+}
@@ -0,0 +1,211 @@
+package subprocess
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// Session is an interactive session with a process that prompts for input.  The Expect methods wait for the output of
+// the process to match a pattern and the Send methods write to the standard input stream of the process.  Start a
+// Session with the public Spawn and SpawnShell functions or the Command.Spawn method.  It includes the following data
+// field:
+//
+//     Session.Timeout - (time.Duration) maximum time that the Expect methods wait for a match.  Default = 30 seconds.
+//                       0 = no timeout
+//
+// The standard output and standard error stream data of the process are matched together in the order that they are
+// read.  Run the process with Command.PTY for programs that read their prompts from the terminal (e.g. passwords).
+type Session struct {
+	Timeout time.Duration
+
+	p          *Process
+	mu         sync.Mutex
+	buf        []byte
+	before     string
+	transcript bytes.Buffer
+	notify     chan struct{}
+}
+
+// Spawn is a public function that starts a system command in an interactive Session.
+// Spawn takes the following parameters:
+//
+//  ctx (context.Context) - the context that bounds the execution of the command
+//  executable (string) - the executable for the command
+//  args (...string) - one or more arguments to the executable as a comma-delimited list of parameters
+//
+// Example:
+//
+//     func main() {
+//         session, err := Spawn(context.Background(), "python3", "-i")
+//         if err != nil {
+//             log.Fatal(err)
+//         }
+//         session.ExpectString(">>> ")
+//         session.SendLine("print(6 * 7)")
+//         match, _ := session.Expect(regexp.MustCompile(`(\d+)\s+>>> `))
+//         fmt.Printf("%s\n", match[1])
+//         session.Close()
+//         response, _ := session.Wait()
+//         fmt.Printf("%d\n", response.ExitCode)
+//     }
+func Spawn(ctx context.Context, executable string, args ...string) (*Session, error) {
+	return NewCommand(executable, args...).Spawn(ctx)
+}
+
+// SpawnShell is a public function that starts a system command with a shell in an interactive Session.  The
+// parameters are defined with the same defaults as the public RunShell function.
+// SpawnShell takes the following parameters:
+//
+//  ctx (context.Context) - the context that bounds the execution of the command
+//  shell (string) - path to the shell.  Defaults = /bin/sh on Linux, macOS; cmd.exe on Windows
+//  shellflag (string) - flag to run executable file with shell. Default = `-c` (macOS/Linux); `/C` (Win)
+//  command (...string) - one or more executable commands, comma-delimited parameter format
+func SpawnShell(ctx context.Context, shell string, shellflag string, command ...string) (*Session, error) {
+	return NewShellCommand(shell, shellflag, command...).Spawn(ctx)
+}
+
+// Spawn starts the command in an interactive Session.  Command.Stdin is replaced with an InputPipe and the
+// Command.Stream handlers still receive the stream data.  The command is not modified.
+func (c *Command) Spawn(ctx context.Context) (*Session, error) {
+	s := &Session{Timeout: 30 * time.Second, notify: make(chan struct{})}
+	sc := *c
+	sc.Stdin = InputPipe()
+	sc.Stream.StdOutChunk = s.output(c.Stream.StdOutChunk)
+	sc.Stream.StdErrChunk = s.output(c.Stream.StdErrChunk)
+	p, err := sc.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.p = p
+
+	return s, nil
+}
+
+// Expect waits for the output of the process to match the regular expression and returns the match followed by the
+// submatches.  The output up to the end of the match is consumed.  An *ExpectError is returned when the timeout passes
+// or the process exits before the output matches.
+func (s *Session) Expect(re *regexp.Regexp) ([]string, error) {
+	var timeout <-chan time.Time
+	if s.Timeout > 0 {
+		timer := time.NewTimer(s.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	exited := false
+	for {
+		s.mu.Lock()
+		if loc := re.FindSubmatchIndex(s.buf); loc != nil {
+			match := make([]string, len(loc)/2)
+			for i := range match {
+				if loc[2*i] >= 0 {
+					match[i] = string(s.buf[loc[2*i]:loc[2*i+1]])
+				}
+			}
+			s.before = string(s.buf[:loc[0]])
+			s.buf = append([]byte(nil), s.buf[loc[1]:]...)
+			s.mu.Unlock()
+			return match, nil
+		}
+		output, notify := string(s.buf), s.notify
+		s.mu.Unlock()
+		if exited {
+			return nil, &ExpectError{Pattern: re.String(), Output: output, EOF: true}
+		}
+		select {
+		case <-notify:
+		case <-s.p.Done():
+			// all of the output was read when the process completed.  Match it once more.
+			exited = true
+		case <-timeout:
+			return nil, &ExpectError{Pattern: re.String(), Output: output, Timeout: true}
+		}
+	}
+}
+
+// ExpectString waits for the output of the process to contain the literal string with the same behavior as Expect
+func (s *Session) ExpectString(str string) error {
+	_, err := s.Expect(regexp.MustCompile(regexp.QuoteMeta(str)))
+	return err
+}
+
+// ExpectEOF waits for the process to exit within the timeout.  An *ExpectError is returned when the timeout passes.
+func (s *Session) ExpectEOF() error {
+	var timeout <-chan time.Time
+	if s.Timeout > 0 {
+		timer := time.NewTimer(s.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case <-s.p.Done():
+		return nil
+	case <-timeout:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return &ExpectError{Pattern: "EOF", Output: string(s.buf), Timeout: true}
+	}
+}
+
+// Before returns the output that preceded the match of the last successful Expect call
+func (s *Session) Before() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.before
+}
+
+// Send writes the string to the standard input stream of the process
+func (s *Session) Send(str string) error {
+	s.mu.Lock()
+	s.transcript.WriteString(str)
+	s.mu.Unlock()
+	_, err := s.p.Stdin().Write([]byte(str))
+	return err
+}
+
+// SendLine writes the string followed by a line ending to the standard input stream of the process
+func (s *Session) SendLine(str string) error {
+	return s.Send(str + "\n")
+}
+
+// Close closes the standard input stream of the process to signal the end of the input
+func (s *Session) Close() error {
+	return s.p.Stdin().Close()
+}
+
+// Transcript returns the output of the process and the input that was sent to it in the order that they occurred.  A
+// pseudo-terminal echoes the input so it appears twice when Command.PTY is defined.
+func (s *Session) Transcript() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.transcript.String()
+}
+
+// Process returns the *Process handle of the session
+func (s *Session) Process() *Process {
+	return s.p
+}
+
+// Wait waits for the process to complete and returns its Response with the same behavior as Process.Wait
+func (s *Session) Wait() (Response, error) {
+	return s.p.Wait()
+}
+
+// output returns a chunk handler that adds the stream data to the unmatched output and the transcript of the session
+// and then calls the chunk handler of the command
+func (s *Session) output(handler func([]byte)) func([]byte) {
+	return func(chunk []byte) {
+		s.mu.Lock()
+		s.buf = append(s.buf, chunk...)
+		s.transcript.Write(chunk)
+		// wake the Expect calls that wait for more output
+		close(s.notify)
+		s.notify = make(chan struct{})
+		s.mu.Unlock()
+		if handler != nil {
+			handler(chunk)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package subprocess
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionExpectAndSend(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		s, err := SpawnShell(context.Background(), "", "", `printf 'name? '; read name; echo "hello $name"; printf 'continue [y/n]? '; read answer; echo "answer=$answer"`)
+		if err != nil {
+			t.Fatalf("[FAIL] Expected the session to start, but received error %v", err)
+		}
+		s.Timeout = 5 * time.Second
+		if err := s.ExpectString("name? "); err != nil {
+			t.Errorf("[FAIL] Expected the 'name? ' prompt, but received error %v", err)
+		}
+		s.SendLine("gopher")
+		match, err := s.Expect(regexp.MustCompile(`hello (\w+)`))
+		if err != nil || len(match) != 2 || match[1] != "gopher" {
+			t.Errorf("[FAIL] Expected the submatch 'gopher', but received %v (%v)", match, err)
+		}
+		if err := s.ExpectString("[y/n]? "); err != nil {
+			t.Errorf("[FAIL] Expected the confirmation prompt, but received error %v", err)
+		}
+		if s.Before() != "\ncontinue " {
+			t.Errorf("[FAIL] Expected Before to return the output preceding the prompt, but received '%q'", s.Before())
+		}
+		s.SendLine("y")
+		if err := s.ExpectEOF(); err != nil {
+			t.Errorf("[FAIL] Expected the process to exit, but received error %v", err)
+		}
+		response, _ := s.Wait()
+		if response.ExitCode != 0 {
+			t.Errorf("[FAIL] Expected exit code 0, but received %d", response.ExitCode)
+		}
+		transcript := "name? gopher\nhello gopher\ncontinue [y/n]? y\nanswer=y\n"
+		if s.Transcript() != transcript {
+			t.Errorf("[FAIL] Expected the transcript '%q', but received '%q'", transcript, s.Transcript())
+		}
+		if response.StdOut != "name? hello gopher\ncontinue [y/n]? answer=y\n" {
+			t.Errorf("[FAIL] Expected the standard output in the Response, but received '%q'", response.StdOut)
+		}
+	}
+}
+
+func TestSessionExpectTimeout(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		s, err := SpawnShell(ctx, "", "", "echo ready; exec sleep 10")
+		if err != nil {
+			t.Fatalf("[FAIL] Expected the session to start, but received error %v", err)
+		}
+		s.Timeout = 200 * time.Millisecond
+		err = s.ExpectString("never")
+		var expectErr *ExpectError
+		if !errors.As(err, &expectErr) || !expectErr.Timeout || expectErr.Output != "ready\n" {
+			t.Errorf("[FAIL] Expected an *ExpectError timeout with the unmatched output, but received %v", err)
+		}
+		cancel()
+		s.Wait()
+	}
+}
+
+func TestSessionExpectEOF(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		s, err := Spawn(context.Background(), "sh", "-c", "echo done")
+		if err != nil {
+			t.Fatalf("[FAIL] Expected the session to start, but received error %v", err)
+		}
+		err = s.ExpectString("prompt")
+		var expectErr *ExpectError
+		if !errors.As(err, &expectErr) || !expectErr.EOF || !strings.Contains(err.Error(), "exited") {
+			t.Errorf("[FAIL] Expected an *ExpectError at EOF, but received %v", err)
+		}
+	}
+}
+
+func TestSessionPTY(t *testing.T) {
+	if runtime.GOOS == "linux" || runtime.GOOS == "darwin" {
+		cmd := NewShellCommand("", "", "stty -echo; printf 'password: '; read secret; echo; echo \"length=${#secret}\"")
+		cmd.PTY = &PTY{}
+		s, err := cmd.Spawn(context.Background())
+		if err != nil {
+			t.Fatalf("[FAIL] Expected the session to start, but received error %v", err)
+		}
+		s.Timeout = 5 * time.Second
+		if err := s.ExpectString("password: "); err != nil {
+			t.Errorf("[FAIL] Expected the password prompt, but received error %v", err)
+		}
+		s.SendLine("hunter2")
+		match, err := s.Expect(regexp.MustCompile(`length=(\d+)`))
+		if err != nil || match[1] != "7" {
+			t.Errorf("[FAIL] Expected length=7, but received %v (%v)", match, err)
+		}
+		s.Close()
+		s.Wait()
+	}
+}
+
+func TestProcessStdin(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		cmd := NewCommand("cat")
+		cmd.Stdin = InputPipe()
+		p, err := cmd.Start(context.Background())
+		if err != nil {
+			t.Fatalf("[FAIL] Expected the process to start, but received error %v", err)
+		}
+		p.Stdin().Write([]byte("piped"))
+		p.Stdin().Close()
+		response, _ := p.Wait()
+		if response.StdOut != "piped" {
+			t.Errorf("[FAIL] Expected 'piped' from the InputPipe, but received '%s'", response.StdOut)
+		}
+		p2, _ := Start(context.Background(), "true")
+		if p2.Stdin() != nil {
+			t.Errorf("[FAIL] Expected a nil Stdin writer without InputPipe")
+		}
+		p2.Wait()
+	}
+}
@@ -0,0 +1,223 @@
+package subprocess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// TestingT is the subset of testing.TB that is used by FakeRunner.AssertExpectations
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// FakeRunner is a Runner that returns scripted Responses without starting system processes.  Define the expected
+// commands with the Expect, ExpectShell, and ExpectFunc methods and the Responses with Expectation.Return.  The calls
+// are recorded and the expectations are checked with AssertExpectations.  A FakeRunner is safe for concurrent use.
+//
+// A command that matches no expectation returns a Response with ExitCode -1 and an *UnexpectedCallError.  The
+// Command.Stream handlers receive the standard output and standard error stream data of the Response.
+type FakeRunner struct {
+	mu           sync.Mutex
+	expectations []*Expectation
+	calls        []Call
+}
+
+// Call is a struct that is defined with a command that was run by a FakeRunner.  It includes the following data fields:
+//
+//     Call.Path - (string) the executable of the command
+//     Call.Args - ([]string) the arguments of the command
+//     Call.Dir - (string) the working directory of the command
+//     Call.Stdin - (string) the standard input stream data of the command
+//     Call.Command - (*Command) the command
+type Call struct {
+	Path    string
+	Args    []string
+	Dir     string
+	Stdin   string
+	Command *Command
+}
+
+// Expectation is an expected command of a FakeRunner with the Responses that are returned for it
+type Expectation struct {
+	// mu is the mutex of the FakeRunner that the expectation belongs to
+	mu          *sync.Mutex
+	description string
+	match       func(c *Command) bool
+	responses   []Response
+	times       int
+	calls       int
+}
+
+// NewFakeRunner is a public function that returns a *FakeRunner without expectations.
+//
+// Example:
+//
+//     func TestDeploy(t *testing.T) {
+//         runner := NewFakeRunner()
+//         runner.Expect("git", "rev-parse", "HEAD").Return(FakeResponse("abc123\n", 0))
+//         runner.ExpectShell("make deploy").Return(FakeResponse("", 2)).Times(1)
+//         err := deploy(runner) // calls runner.Run(ctx, NewCommand("git", "rev-parse", "HEAD")) ...
+//         if err == nil {
+//             t.Error("expected the failed deploy to return an error")
+//         }
+//         runner.AssertExpectations(t)
+//     }
+func NewFakeRunner() *FakeRunner {
+	return &FakeRunner{}
+}
+
+// FakeResponse is a public function that returns a Response with the standard output stream data and the exit status
+// code.  Response.Err is an *ExitError when the exit status code is not zero.
+func FakeResponse(stdout string, exitCode int) Response {
+	res := Response{StdOut: stdout, ExitCode: exitCode, StdOutBytes: int64(len(stdout))}
+	if exitCode != 0 {
+		res.Err = &ExitError{ExitCode: exitCode}
+	}
+	return res
+}
+
+// Expect adds an expectation for a command with the executable and exactly the arguments
+func (f *FakeRunner) Expect(executable string, args ...string) *Expectation {
+	return f.add(fmt.Sprintf("%s %q", executable, args), func(c *Command) bool {
+		return c.Path == executable && slices.Equal(c.Args, args)
+	})
+}
+
+// ExpectShell adds an expectation for a shell command that was defined with NewShellCommand, the default shell and
+// shell flag, and the command strings.  Use Expect for the commands of other shells.
+func (f *FakeRunner) ExpectShell(command ...string) *Expectation {
+	shell, shellflag := getShell("", "")
+	shellExecString := strings.Join(command, " ")
+	return f.add(fmt.Sprintf("shell %q", shellExecString), func(c *Command) bool {
+		return c.Path == shell && len(c.Args) == 2 && c.Args[0] == shellflag && c.Args[1] == shellExecString
+	})
+}
+
+// ExpectFunc adds an expectation for the commands that the match function returns true for
+func (f *FakeRunner) ExpectFunc(description string, match func(c *Command) bool) *Expectation {
+	return f.add(description, match)
+}
+
+// Run returns the next Response of the first expectation that matches the command and has calls left
+func (f *FakeRunner) Run(ctx context.Context, c *Command) Response {
+	call := Call{Path: c.Path, Args: slices.Clone(c.Args), Dir: c.Dir, Command: c}
+	if stdin, closeInput, err := c.Stdin.open(); err == nil {
+		if stdin != nil {
+			data, _ := io.ReadAll(stdin)
+			call.Stdin = string(data)
+		}
+		closeInput()
+	}
+	f.mu.Lock()
+	f.calls = append(f.calls, call)
+	var res Response
+	found := false
+	for _, e := range f.expectations {
+		if (e.times == 0 || e.calls < e.times) && e.match(c) {
+			res = e.next()
+			found = true
+			break
+		}
+	}
+	f.mu.Unlock()
+	if !found {
+		return Response{ExitCode: -1, Err: &UnexpectedCallError{Executable: c.Path, Args: call.Args}}
+	}
+
+	return streamResponse(c, res)
+}
+
+// Calls returns the commands that were run by the FakeRunner in order
+func (f *FakeRunner) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.calls)
+}
+
+// Verify returns an error that lists the expectations that were called fewer times than expected.  An expectation
+// without Times must be called at least once.
+func (f *FakeRunner) Verify() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var errs []error
+	for _, e := range f.expectations {
+		expected := max(e.times, 1)
+		if e.calls < expected {
+			errs = append(errs, fmt.Errorf("subprocess: expected %s to be called %d time(s), but it was called %d time(s)",
+				e.description, expected, e.calls))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// AssertExpectations reports the expectations that were not met as test errors
+func (f *FakeRunner) AssertExpectations(t TestingT) {
+	t.Helper()
+	if err := f.Verify(); err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+// add adds an expectation to the FakeRunner
+func (f *FakeRunner) add(description string, match func(c *Command) bool) *Expectation {
+	e := &Expectation{mu: &f.mu, description: description, match: match}
+	f.mu.Lock()
+	f.expectations = append(f.expectations, e)
+	f.mu.Unlock()
+
+	return e
+}
+
+// Return adds a Response that is returned for the expectation.  The Responses are returned in order and the last
+// Response is repeated.  A zero Response is returned when no Response is defined.
+func (e *Expectation) Return(res Response) *Expectation {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.responses = append(e.responses, res)
+	return e
+}
+
+// Times defines the number of times that the expectation must be called.  Later calls are matched with the next
+// expectations.
+func (e *Expectation) Times(n int) *Expectation {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.times = n
+	return e
+}
+
+// next returns the Response for the next call of the expectation
+func (e *Expectation) next() Response {
+	e.calls++
+	if len(e.responses) == 0 {
+		return Response{}
+	}
+	return e.responses[min(e.calls, len(e.responses))-1]
+}
+
+// streamResponse passes the standard output and standard error stream data of a scripted Response to the
+// Command.Stream handlers and returns the Response with the Stream.Discard behavior applied
+func streamResponse(c *Command, res Response) Response {
+	stdout, outlines := getStreamWriter(newCaptureBuffer(Capture{Policy: CaptureHead}), c.Stream.StdOutLine, c.Stream.StdOutChunk)
+	stderr, errlines := getStreamWriter(newCaptureBuffer(Capture{Policy: CaptureHead}), c.Stream.StdErrLine, c.Stream.StdErrChunk)
+	io.WriteString(stdout, res.StdOut)
+	io.WriteString(stderr, res.StdErr)
+	if outlines != nil {
+		outlines.flush()
+	}
+	if errlines != nil {
+		errlines.flush()
+	}
+	if c.Stream.Discard {
+		res.StdOut, res.StdErr = "", ""
+	}
+
+	return res
+}
@@ -0,0 +1,112 @@
+package subprocess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// recordingT records the errors that are reported by FakeRunner.AssertExpectations
+type recordingT struct {
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestFakeRunner(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.Expect("git", "rev-parse", "HEAD").Return(FakeResponse("abc123\n", 0))
+	runner.ExpectShell("make", "deploy").Return(FakeResponse("", 2)).Return(FakeResponse("deployed\n", 0))
+	var r Runner = runner
+
+	response := r.Run(context.Background(), NewCommand("git", "rev-parse", "HEAD"))
+	if response.StdOut != "abc123\n" || response.Err != nil {
+		t.Errorf("[FAIL] Expected the scripted Response, but received '%s' (%v)", response.StdOut, response.Err)
+	}
+	response = r.Run(context.Background(), NewShellCommand("", "", "make", "deploy"))
+	var exitErr *ExitError
+	if response.ExitCode != 2 || !errors.As(response.Err, &exitErr) {
+		t.Errorf("[FAIL] Expected the first scripted Response with exit code 2, but received %d (%v)", response.ExitCode, response.Err)
+	}
+	response = r.Run(context.Background(), NewShellCommand("", "", "make deploy"))
+	if response.StdOut != "deployed\n" {
+		t.Errorf("[FAIL] Expected the second scripted Response, but received '%s'", response.StdOut)
+	}
+	var callErr *UnexpectedCallError
+	response = r.Run(context.Background(), NewCommand("git", "-c", "make deploy"))
+	if !errors.As(response.Err, &callErr) {
+		t.Errorf("[FAIL] Expected a command that is not run with the shell to not match ExpectShell, but received %v", response.Err)
+	}
+	response = r.Run(context.Background(), NewCommand("git", "push"))
+	if response.ExitCode != -1 || !errors.As(response.Err, &callErr) || callErr.Executable != "git" {
+		t.Errorf("[FAIL] Expected an *UnexpectedCallError, but received %v", response.Err)
+	}
+
+	calls := runner.Calls()
+	if len(calls) != 5 || calls[0].Path != "git" || calls[4].Args[0] != "push" {
+		t.Errorf("[FAIL] Expected 5 recorded calls, but received %v", calls)
+	}
+	runner.AssertExpectations(t)
+}
+
+func TestFakeRunnerTimes(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.Expect("curl", "https://example.com").Return(FakeResponse("", 7)).Times(2)
+	runner.Expect("curl", "https://example.com").Return(FakeResponse("ok", 0))
+	runner.ExpectFunc("any rm", func(c *Command) bool { return c.Path == "rm" })
+
+	var codes []int
+	for i := 0; i < 3; i++ {
+		codes = append(codes, runner.Run(context.Background(), NewCommand("curl", "https://example.com")).ExitCode)
+	}
+	if codes[0] != 7 || codes[1] != 7 || codes[2] != 0 {
+		t.Errorf("[FAIL] Expected exit codes [7 7 0], but received %v", codes)
+	}
+	rt := &recordingT{}
+	runner.AssertExpectations(rt)
+	if len(rt.errors) != 1 || !strings.Contains(rt.errors[0], "any rm") {
+		t.Errorf("[FAIL] Expected an unmet expectation error for 'any rm', but received %v", rt.errors)
+	}
+}
+
+func TestFakeRunnerStreamAndStdin(t *testing.T) {
+	runner := NewFakeRunner()
+	runner.Expect("wc", "-l").Return(Response{StdOut: "one\ntwo", StdErr: "warning\n"})
+	cmd := NewCommand("wc", "-l")
+	cmd.Stdin = InputString("input data")
+	var lines []string
+	cmd.Stream = Stream{StdOutLine: func(line string) { lines = append(lines, line) }, Discard: true}
+	response := runner.Run(context.Background(), cmd)
+	if len(lines) != 2 || lines[1] != "two" {
+		t.Errorf("[FAIL] Expected the stream handler to receive 2 lines, but received %v", lines)
+	}
+	if response.StdOut != "" || response.StdErr != "" {
+		t.Errorf("[FAIL] Expected Stream.Discard to leave the output empty")
+	}
+	if calls := runner.Calls(); calls[0].Stdin != "input data" {
+		t.Errorf("[FAIL] Expected the recorded standard input 'input data', but received '%s'", calls[0].Stdin)
+	}
+}
+
+func TestFakeRunnerConcurrentReturn(t *testing.T) {
+	// the Responses are added while the commands run and must not race with them (go test -race)
+	runner := NewFakeRunner()
+	expectation := runner.Expect("curl", "https://example.com").Return(FakeResponse("", 7))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			runner.Run(context.Background(), NewCommand("curl", "https://example.com"))
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		expectation.Return(FakeResponse("ok", 0)).Times(0)
+	}
+	<-done
+}
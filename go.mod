@@ -0,0 +1,3 @@
+module github.com/go-rillas/subprocess
+
+go 1.21
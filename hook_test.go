@@ -0,0 +1,96 @@
+package subprocess
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingHook is a Hook that keeps the events that it receives
+type recordingHook struct {
+	mu       sync.Mutex
+	started  []StartEvent
+	finished []FinishEvent
+}
+
+func (h *recordingHook) Started(ctx context.Context, e StartEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.started = append(h.started, e)
+}
+
+func (h *recordingHook) Finished(ctx context.Context, e FinishEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.finished = append(h.finished, e)
+}
+
+func TestCommandHook(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		hook := &recordingHook{}
+		cmd := NewShellCommand("", "", "echo out; echo error >&2; exit 3")
+		cmd.Hook = hook
+		cmd.Dir = "/"
+		cmd.Setenv("GITHUB_TOKEN", "ghp_secret")
+		response := cmd.Run(context.Background())
+		if len(hook.started) != 1 || len(hook.finished) != 1 {
+			t.Fatalf("[FAIL] Expected one start and one finish event, but received %d and %d", len(hook.started), len(hook.finished))
+		}
+		started := hook.started[0]
+		if started.PID == 0 || started.Dir != "/" || started.Path != "/bin/sh" || started.StartTime.IsZero() {
+			t.Errorf("[FAIL] Expected the start event to hold the process data, but received %+v", started)
+		}
+		for _, kv := range started.Env {
+			if strings.Contains(kv, "ghp_secret") {
+				t.Errorf("[FAIL] Expected the GITHUB_TOKEN value to be redacted, but received '%s'", kv)
+			}
+		}
+		finished := hook.finished[0]
+		if finished.PID != started.PID || finished.ExitCode != 3 || finished.Err == nil {
+			t.Errorf("[FAIL] Expected the finish event to hold the exit status code 3, but received %+v", finished)
+		}
+		if finished.StdOutBytes != 4 || finished.StdErrBytes != 6 {
+			t.Errorf("[FAIL] Expected 4 stdout and 6 stderr bytes, but received %d and %d", finished.StdOutBytes, finished.StdErrBytes)
+		}
+		if finished.Duration != response.Duration {
+			t.Errorf("[FAIL] Expected the duration of the Response, but received %s", finished.Duration)
+		}
+	}
+}
+
+func TestCommandHookStartError(t *testing.T) {
+	hook := &recordingHook{}
+	cmd := NewCommand("bogus-executable-that-does-not-exist")
+	cmd.Hook = hook
+	cmd.Run(context.Background())
+	if len(hook.started) != 0 || len(hook.finished) != 1 {
+		t.Fatalf("[FAIL] Expected only a finish event, but received %d start and %d finish events", len(hook.started), len(hook.finished))
+	}
+	if hook.finished[0].ExitCode != -1 || hook.finished[0].Err == nil || hook.finished[0].PID != 0 {
+		t.Errorf("[FAIL] Expected a finish event with the start error, but received %+v", hook.finished[0])
+	}
+}
+
+func TestSetDefaultHook(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		hook := &recordingHook{}
+		SetDefaultHook(hook)
+		defer SetDefaultHook(nil)
+		Run("echo", "--password", "hunter2")
+		if len(hook.finished) != 1 {
+			t.Fatalf("[FAIL] Expected the default hook to receive the events, but received %d finish events", len(hook.finished))
+		}
+		if args := hook.finished[0].Args; len(args) != 2 || args[1] != Redacted {
+			t.Errorf("[FAIL] Expected the password argument to be redacted, but received %v", args)
+		}
+		RunShell("", "", "echo --password hunter2")
+		if args := hook.finished[1].Args; len(args) != 2 || strings.Contains(args[1], "hunter2") {
+			t.Errorf("[FAIL] Expected the password in the shell command to be redacted, but received %v", args)
+		}
+		if hook.finished[0].Env != nil {
+			t.Errorf("[FAIL] Expected no environment for an inherited environment, but received %v", hook.finished[0].Env)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package subprocess
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+)
+
+// Input is a struct that defines the data that is passed to the standard input stream of a process.  Define an Input
+// with the public InputString, InputBytes, InputReader, InputFile, and InputPipe functions.  The zero value Input
+// passes no data to the process.
+type Input struct {
+	data   []byte
+	reader io.Reader
+	path   string
+	pipe   bool
+}
+
+// InputString returns an Input that passes a string to the standard input stream of a process
+func InputString(s string) Input {
+	return Input{data: []byte(s)}
+}
+
+// InputBytes returns an Input that passes a byte slice to the standard input stream of a process
+func InputBytes(b []byte) Input {
+	return Input{data: b}
+}
+
+// InputReader returns an Input that passes the data read from an io.Reader to the standard input stream of a process.
+// The reader is read until io.EOF or until the process exits.  Unlike the other Input types, the data of an io.Reader
+// can only be passed to one process.
+func InputReader(r io.Reader) Input {
+	return Input{reader: r}
+}
+
+// InputFile returns an Input that passes the contents of a file to the standard input stream of a process.  The file
+// is opened when the process is started.
+func InputFile(path string) Input {
+	return Input{path: path}
+}
+
+// InputPipe returns an Input that connects the standard input stream of a process to a pipe.  Write to the pipe with the
+// writer that is returned by Process.Stdin while the process runs and close it to signal the end of the input.
+func InputPipe() Input {
+	return Input{pipe: true}
+}
+
+// open returns the io.Reader for the Input and a function that releases the resources that were opened for it
+func (in Input) open() (io.Reader, func(), error) {
+	if in.path != "" {
+		f, err := os.Open(in.path)
+		if err != nil {
+			return nil, nil, err
+		}
+		return f, func() { f.Close() }, nil
+	}
+	if in.data != nil {
+		return bytes.NewReader(in.data), func() {}, nil
+	}
+
+	return in.reader, func() {}, nil
+}
+
+// RunInput is a public function that executes a system command with the same behavior as RunContext and passes the
+// input parameter data to the standard input stream of the process.  The input data is written while the standard output
+// and standard error stream data are collected so that large inputs do not block the process.
+// RunInput takes the following parameters:
+//
+//  ctx (context.Context) - the context that bounds the execution of the command
+//  input (Input) - the data for the standard input stream
+//  executable (string) - the executable for the command
+//  args (...string) - one or more arguments to the executable as a comma-delimited list of parameters
+//
+// Example:
+//
+//     func main() {
+//         response := RunInput(context.Background(), InputString(`{"name": "subprocess"}`), "jq", ".name")
+//         fmt.Printf("%s\n", response.StdOut)
+//     }
+func RunInput(ctx context.Context, input Input, executable string, args ...string) Response {
+	c := NewCommand(executable, args...)
+	c.Stdin = input
+
+	return c.Run(ctx)
+}
+
+// RunShellInput is a public function that executes a system command with a shell with the same behavior as
+// RunShellContext and passes the input parameter data to the standard input stream of the shell process.
+// RunShellInput takes the following parameters:
+//
+//  ctx (context.Context) - the context that bounds the execution of the command
+//  input (Input) - the data for the standard input stream
+//  shell (string) - path to the shell.  Defaults = /bin/sh on Linux, macOS; cmd.exe on Windows
+//  shellflag (string) - flag to run executable file with shell. Default = `-c` (macOS/Linux); `/C` (Win)
+//  command (...string) - one or more executable commands, comma-delimited parameter format
+//
+// Example (macOS/Linux):
+//
+//     func main() {
+//         response := RunShellInput(context.Background(), InputFile("names.txt"), "", "", "sort | uniq")
+//         fmt.Printf("%s\n", response.StdOut)
+//     }
+func RunShellInput(ctx context.Context, input Input, shell string, shellflag string, command ...string) Response {
+	c := NewShellCommand(shell, shellflag, command...)
+	c.Stdin = input
+
+	return c.Run(ctx)
+}
@@ -0,0 +1,71 @@
+package subprocess
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRunInputUnixString(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		response := RunInput(context.Background(), InputString("b\na\nc\n"), "sort")
+
+		if response.ExitCode != 0 {
+			t.Errorf("[FAIL] Expected command to return 0 exit status code and instead it returned %d", response.ExitCode)
+		}
+		if response.StdOut != "a\nb\nc\n" {
+			t.Errorf("[FAIL] Expected std out to be the sorted input and it was actually '%s'", response.StdOut)
+		}
+	}
+}
+
+func TestRunInputUnixLargeBytes(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		// larger than the pipe buffers so the input must be written while the output is collected
+		input := []byte(strings.Repeat("0123456789abcdef\n", 1<<16))
+		response := RunInput(context.Background(), InputBytes(input), "cat")
+
+		if response.ExitCode != 0 {
+			t.Errorf("[FAIL] Expected command to return 0 exit status code and instead it returned %d", response.ExitCode)
+		}
+		if response.StdOut != string(input) {
+			t.Errorf("[FAIL] Expected std out to hold %d bytes of input and it held %d bytes", len(input), len(response.StdOut))
+		}
+	}
+}
+
+func TestRunShellInputUnixFile(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		path := filepath.Join(t.TempDir(), "input.txt")
+		if err := os.WriteFile(path, []byte("one\ntwo\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		response := RunShellInput(context.Background(), InputFile(path), "", "", "wc -l")
+
+		if strings.TrimSpace(response.StdOut) != "2" {
+			t.Errorf("[FAIL] Expected std out to be the input line count '2' and it was actually '%s'", response.StdOut)
+		}
+	}
+}
+
+func TestRunInputMissingFile(t *testing.T) {
+	response := RunInput(context.Background(), InputFile("bogus-input-file.txt"), "climock")
+
+	if _, ok := response.Err.(*StartError); !ok {
+		t.Errorf("[FAIL] Expected missing input file to return a *StartError and instead it returned %#v", response.Err)
+	}
+	if response.ExitCode != -1 {
+		t.Errorf("[FAIL] Expected missing input file to return -1 exit status code and instead it returned %d", response.ExitCode)
+	}
+}
+
+func TestRunInputReader(t *testing.T) {
+	response := RunInput(context.Background(), InputReader(strings.NewReader("reader input\n")), "climock", "--stdin", "--stdout", "Test")
+
+	if response.StdOut != "reader input\nTest" {
+		t.Errorf("[FAIL] Expected mock std out to be the reader input followed by 'Test' and it was actually '%s'", response.StdOut)
+	}
+}
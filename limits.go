@@ -0,0 +1,45 @@
+package subprocess
+
+import (
+	"fmt"
+)
+
+// Resource is a system resource that is limited with Command.Limits
+type Resource int
+
+const (
+	// LimitCPU is the CPU time of the process in seconds.  The process receives SIGXCPU when the limit is exceeded.
+	LimitCPU Resource = iota
+	// LimitFileSize is the size in bytes of the largest file that the process may write.  The process receives SIGXFSZ
+	// when the limit is exceeded.
+	LimitFileSize
+	// LimitAddressSpace is the size in bytes of the virtual memory of the process.  Allocations fail when the limit is
+	// exceeded.
+	LimitAddressSpace
+	// LimitOpenFiles is the number of file descriptors that the process may open
+	LimitOpenFiles
+	// LimitCoreSize is the size in bytes of the largest core dump file that the process may write.  0 disables core
+	// dumps.
+	LimitCoreSize
+	// LimitProcesses is the number of processes that the user of the process may run
+	LimitProcesses
+)
+
+// String returns the name of the resource
+func (r Resource) String() string {
+	switch r {
+	case LimitCPU:
+		return "cpu"
+	case LimitFileSize:
+		return "fsize"
+	case LimitAddressSpace:
+		return "as"
+	case LimitOpenFiles:
+		return "nofile"
+	case LimitCoreSize:
+		return "core"
+	case LimitProcesses:
+		return "nproc"
+	}
+	return fmt.Sprintf("Resource(%d)", int(r))
+}
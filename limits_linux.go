@@ -0,0 +1,200 @@
+package subprocess
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// limitsSupported is true because resource limits are applied by a helper process on Linux
+const limitsSupported = true
+
+// limitsHelperEnv is the environment variable that runs the current executable as the helper process that applies the
+// Command.Limits and executes the command.  It holds the encoded limits.
+const limitsHelperEnv = "GO_SUBPROCESS_LIMITS"
+
+// limitsHelperFD is the file descriptor of the pipe that the helper process reports its errors to.  It is closed when
+// the command is executed.
+const limitsHelperFD = 3
+
+// rlimitResources maps the resources to the resource numbers of the system
+var rlimitResources = map[Resource]int{
+	LimitCPU:          syscall.RLIMIT_CPU,
+	LimitFileSize:     syscall.RLIMIT_FSIZE,
+	LimitAddressSpace: syscall.RLIMIT_AS,
+	LimitOpenFiles:    syscall.RLIMIT_NOFILE,
+	LimitCoreSize:     syscall.RLIMIT_CORE,
+	LimitProcesses:    rlimitNproc,
+}
+
+// the current executable runs as the limits helper process before any other code of the program runs
+func init() {
+	if encoded, ok := os.LookupEnv(limitsHelperEnv); ok {
+		runLimitsHelper(encoded)
+	}
+}
+
+// helperError is an error that was reported by the limits helper process
+type helperError struct {
+	msg   string
+	errno syscall.Errno
+}
+
+func (e *helperError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the system call error number of the error.  nil when the error was not raised by a system call.
+func (e *helperError) Unwrap() error {
+	if e.errno == 0 {
+		return nil
+	}
+	return e.errno
+}
+
+// setLimitsHelper replaces the executable of the command with the current executable that applies the limits in the
+// process and then executes the command, so that the command never runs without the limits.  It returns the read end
+// of the pipe that the helper process reports its errors to.
+func setLimitsHelper(cmd *exec.Cmd, limits map[Resource]uint64) (*os.File, error) {
+	encoded := make([]string, 0, len(limits))
+	for resource, value := range limits {
+		if _, ok := rlimitResources[resource]; !ok {
+			return nil, fmt.Errorf("subprocess: unknown resource %v", resource)
+		}
+		encoded = append(encoded, strconv.Itoa(int(resource))+"="+strconv.FormatUint(value, 10))
+	}
+	if cmd.Err != nil {
+		// the executable lookup error is returned by cmd.Start
+		return nil, nil
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	cmd.Env = append(env[:len(env):len(env)], limitsHelperEnv+"="+strings.Join(encoded, ","))
+	// the helper process executes the path in its first argument with the remaining arguments
+	cmd.Args = append([]string{cmd.Path}, cmd.Args...)
+	cmd.Path = "/proc/self/exe"
+	cmd.ExtraFiles = []*os.File{w}
+
+	return r, nil
+}
+
+// waitLimitsHelper closes the write end of the helper pipe in the current process and waits until the helper process
+// has executed the command or has failed.  The read end of the pipe is closed.
+func waitLimitsHelper(cmd *exec.Cmd, status *os.File, started bool) error {
+	if status == nil {
+		return nil
+	}
+	defer status.Close()
+	cmd.ExtraFiles[0].Close()
+	if !started {
+		return nil
+	}
+	// the pipe is closed without data when the command is executed
+	data, err := io.ReadAll(status)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	number, msg, _ := strings.Cut(string(data), ":")
+	errno, _ := strconv.Atoi(number)
+
+	return &helperError{msg: msg, errno: syscall.Errno(errno)}
+}
+
+// runLimitsHelper applies the encoded limits to the current process and executes the command in its arguments.  The
+// errors are written to the helper pipe and the process exits.
+func runLimitsHelper(encoded string) {
+	status := os.NewFile(limitsHelperFD, "limits")
+	syscall.CloseOnExec(limitsHelperFD)
+	fail := func(err error) {
+		var errno syscall.Errno
+		errors.As(err, &errno)
+		status.WriteString(strconv.Itoa(int(errno)) + ":" + err.Error())
+		os.Exit(127)
+	}
+	if len(os.Args) < 2 {
+		fail(errors.New("subprocess: the limits helper process was started without an executable"))
+	}
+	limits := make(map[Resource]uint64)
+	for _, limit := range strings.Split(encoded, ",") {
+		resource, value, _ := strings.Cut(limit, "=")
+		r, err := strconv.Atoi(resource)
+		if err != nil {
+			fail(fmt.Errorf("subprocess: invalid limit %q", limit))
+		}
+		if limits[Resource(r)], err = strconv.ParseUint(value, 10, 64); err != nil {
+			fail(fmt.Errorf("subprocess: invalid limit %q", limit))
+		}
+	}
+	// the environment is prepared before the limits are applied because the address space limit can make allocations
+	// fail
+	var env []string
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, limitsHelperEnv+"=") {
+			env = append(env, kv)
+		}
+	}
+	if err := setLimits(limits); err != nil {
+		fail(err)
+	}
+	err := syscall.Exec(os.Args[0], os.Args[1:], env)
+	fail(fmt.Errorf("subprocess: unable to execute %s: %w", os.Args[0], err))
+}
+
+// setLimits applies the resource limits to the current process.  The soft and hard limits are both defined with the
+// value.
+func setLimits(limits map[Resource]uint64) error {
+	for resource, value := range limits {
+		number, ok := rlimitResources[resource]
+		if !ok {
+			return fmt.Errorf("subprocess: unknown resource %v", resource)
+		}
+		rlim := syscall.Rlimit{Cur: value, Max: value}
+		if resource == LimitCPU {
+			// the process is killed with SIGKILL instead of SIGXCPU when the soft limit equals the hard limit
+			if value < math.MaxUint64 {
+				// the unlimited value (RLIM_INFINITY) keeps the hard limit instead of overflowing to zero
+				rlim.Max = value + 1
+			}
+		}
+		// syscall.Setrlimit keeps the open file limit when the command is executed
+		if err := syscall.Setrlimit(number, &rlim); err != nil {
+			return fmt.Errorf("subprocess: unable to set the %v limit: %w", resource, err)
+		}
+	}
+
+	return nil
+}
+// limitExceeded returns true when the process was terminated because it exceeded the CPU time or file size limits
+func limitExceeded(res Response, limits map[Resource]uint64) bool {
+	if !res.Signaled {
+		return false
+	}
+	cpu, cpuLimited := limits[LimitCPU]
+	_, fileSizeLimited := limits[LimitFileSize]
+	switch res.Signal {
+	case syscall.SIGXCPU:
+		return cpuLimited
+	case syscall.SIGXFSZ:
+		return fileSizeLimited
+	case syscall.SIGKILL:
+		// the hard CPU time limit kills the process when it handles SIGXCPU
+		return cpuLimited && uint64((res.Usage.UserTime+res.Usage.SystemTime).Seconds()) >= cpu+1
+	}
+
+	return false
+}
@@ -0,0 +1,65 @@
+package subprocess
+
+import (
+	"context"
+	"errors"
+	"math"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestCommandLimitsCPU(t *testing.T) {
+	cmd := NewShellCommand("", "", "while :; do :; done")
+	cmd.Limits = map[Resource]uint64{LimitCPU: 1}
+	response := cmd.Run(context.Background())
+	if !response.Signaled || response.Signal != syscall.SIGXCPU {
+		t.Errorf("[FAIL] Expected the process to be terminated by SIGXCPU, but received %s", response.String())
+	}
+	if !response.LimitExceeded {
+		t.Errorf("[FAIL] Expected LimitExceeded to be true when the CPU time limit is exceeded")
+	}
+}
+
+func TestCommandLimitsFileSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out")
+	cmd := NewShellCommand("", "", "exec head -c 100000 /dev/zero > "+path)
+	cmd.Limits = map[Resource]uint64{LimitFileSize: 1000}
+	response := cmd.Run(context.Background())
+	if response.Signal != syscall.SIGXFSZ || !response.LimitExceeded {
+		t.Errorf("[FAIL] Expected the process to exceed the file size limit, but received %s", response.String())
+	}
+}
+
+func TestCommandLimitsHelperEnvironment(t *testing.T) {
+	cmd := NewCommand("env")
+	cmd.Limits = map[Resource]uint64{LimitCoreSize: 0}
+	response := cmd.Run(context.Background())
+	if response.Err != nil || strings.Contains(response.StdOut, limitsHelperEnv) {
+		t.Errorf("[FAIL] Expected the helper environment variable to be removed, but received %v\n%s", response.Err, response.StdOut)
+	}
+}
+
+func TestCommandLimitsError(t *testing.T) {
+	// the open file limit cannot be raised above the system maximum
+	cmd := NewCommand("true")
+	cmd.Limits = map[Resource]uint64{LimitOpenFiles: 1 << 62}
+	response := cmd.Run(context.Background())
+	var startErr *StartError
+	if !errors.As(response.Err, &startErr) || response.ExitCode != -1 {
+		t.Errorf("[FAIL] Expected a *StartError when the limits cannot be applied, but received %v", response.Err)
+	}
+	if !errors.Is(response.Err, syscall.EPERM) {
+		t.Errorf("[FAIL] Expected the error to wrap EPERM, but received %v", response.Err)
+	}
+}
+
+func TestCommandLimitsCPUUnlimited(t *testing.T) {
+	cmd := NewShellCommand("", "", "ulimit -t; ulimit -H -t")
+	cmd.Limits = map[Resource]uint64{LimitCPU: math.MaxUint64}
+	response := cmd.Run(context.Background())
+	if response.StdOut != "unlimited\nunlimited\n" {
+		t.Errorf("[FAIL] Expected the unlimited CPU time limit to keep the hard limit, but received %q (%v)", response.StdOut, response.Err)
+	}
+}
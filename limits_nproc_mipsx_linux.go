@@ -0,0 +1,5 @@
+//go:build linux && (mips || mipsle || mips64 || mips64le)
+
+package subprocess
+
+const rlimitNproc = 0x8
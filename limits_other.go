@@ -0,0 +1,25 @@
+//go:build !linux
+
+package subprocess
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// limitsSupported is false because resource limits are only supported on Linux
+const limitsSupported = false
+
+// setLimitsHelper returns an error because resource limits are only supported on Linux
+func setLimitsHelper(cmd *exec.Cmd, limits map[Resource]uint64) (*os.File, error) {
+	return nil, errors.New("subprocess: Limits is only supported on Linux")
+}
+
+func waitLimitsHelper(cmd *exec.Cmd, status *os.File, started bool) error {
+	return nil
+}
+
+func limitExceeded(res Response, limits map[Resource]uint64) bool {
+	return false
+}
@@ -0,0 +1,34 @@
+package subprocess
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCommandLimitsOpenFiles(t *testing.T) {
+	cmd := NewShellCommand("", "", "ulimit -n")
+	cmd.Limits = map[Resource]uint64{LimitOpenFiles: 64, LimitCoreSize: 0}
+	response := cmd.Run(context.Background())
+	if runtime.GOOS != "linux" {
+		var startErr *StartError
+		if !errors.As(response.Err, &startErr) {
+			t.Errorf("[FAIL] Expected a *StartError for Limits on %s, but received %v", runtime.GOOS, response.Err)
+		}
+		return
+	}
+	if strings.TrimSpace(response.StdOut) != "64" {
+		t.Errorf("[FAIL] Expected an open file limit of 64, but received '%s' (%v)", response.StdOut, response.Err)
+	}
+	if response.LimitExceeded {
+		t.Errorf("[FAIL] Expected LimitExceeded to be false for a process that completed")
+	}
+}
+
+func TestResourceString(t *testing.T) {
+	if LimitCPU.String() != "cpu" || LimitOpenFiles.String() != "nofile" || Resource(99).String() != "Resource(99)" {
+		t.Errorf("[FAIL] Expected the resource names, but received %s, %s, %s", LimitCPU, LimitOpenFiles, Resource(99))
+	}
+}
@@ -0,0 +1,17 @@
+package subprocess
+
+import (
+	"os"
+	"testing"
+
+	"github.com/go-rillas/subprocess/subprocesstest"
+)
+
+// TestMain installs the climock mock executable that the tests run.  The test binary runs as the parent process of
+// TestCommandLinuxPdeathsig when it is started with the pdeathsigParentEnv environment variable.
+func TestMain(m *testing.M) {
+	if os.Getenv(pdeathsigParentEnv) != "" {
+		pdeathsigParent()
+	}
+	subprocesstest.Main(m, "climock")
+}
@@ -0,0 +1,130 @@
+package subprocess
+
+import (
+	"context"
+	"os"
+)
+
+// Pipeline is a struct that is defined with commands that are connected without a shell.  The standard output stream
+// of each command is connected to the standard input stream of the next command.  It includes the following data
+// fields:
+//
+//     Pipeline.Commands - ([]*Command) the stages of the pipeline in order
+//     Pipeline.PipeFail - (bool) the exit status code of the pipeline is the exit status code of the last stage that
+//                         failed instead of the exit status code of the last stage (same as `set -o pipefail`)
+//
+// The Command.Stdin of the first stage is passed to the pipeline.  The Command.Stdin of the other stages is ignored.
+type Pipeline struct {
+	Commands []*Command
+	PipeFail bool
+}
+
+// PipelineResponse is a struct that is defined with data on the execution of a Pipeline.  It is returned from
+// Pipeline.Run with the following data fields:
+//
+//     PipelineResponse.StdOut - (string) standard output stream of the last stage cast to a string
+//     PipelineResponse.ExitCode - (int) exit status code of the pipeline
+//     PipelineResponse.Err - (error) Response.Err of the stage that defined the exit status code of the pipeline
+//     PipelineResponse.Stages - ([]Response) Response of each stage that was started, in order.  Response.StdOut is
+//                               empty for all stages except the last.
+type PipelineResponse struct {
+	StdOut   string
+	ExitCode int
+	Err      error
+	Stages   []Response
+}
+
+// NewPipeline is a public function that returns a *Pipeline with the commands connected in order.
+// NewPipeline takes the following parameters:
+//
+//  commands (...*Command) - the stages of the pipeline in order
+//
+// Example (macOS/Linux):
+//
+//     func main() {
+//         pipeline := NewPipeline(
+//             NewCommand("git", "log", "--format=%an"),
+//             NewCommand("sort"),
+//             NewCommand("uniq", "-c"),
+//         )
+//         pipeline.PipeFail = true
+//         response := pipeline.Run(context.Background())
+//         fmt.Printf("%s\n", response.StdOut)
+//         fmt.Printf("%d\n", response.ExitCode)
+//     }
+func NewPipeline(commands ...*Command) *Pipeline {
+	return &Pipeline{Commands: commands}
+}
+
+// Run executes the stages of the pipeline concurrently and returns the PipelineResponse after all stages have
+// completed.  All stages are killed if the context is canceled or its deadline is exceeded, or if a stage could not be
+// started.
+func (pl *Pipeline) Run(ctx context.Context) PipelineResponse {
+	var res PipelineResponse
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var processes []*Process
+	var pipeIn *os.File
+	var startErr error
+	for i, c := range pl.Commands {
+		var pipeOut, nextIn *os.File
+		if i < len(pl.Commands)-1 {
+			if nextIn, pipeOut, startErr = os.Pipe(); startErr != nil {
+				startErr = &StartError{Executable: c.Path, Err: startErr}
+				break
+			}
+		}
+		p, err := c.start(ctx, pipeIn, pipeOut)
+		// the processes hold their own copies of the pipe files
+		if pipeIn != nil {
+			pipeIn.Close()
+		}
+		if pipeOut != nil {
+			pipeOut.Close()
+		}
+		pipeIn = nextIn
+		if err != nil {
+			startErr = err
+			break
+		}
+		processes = append(processes, p)
+	}
+	if pipeIn != nil {
+		pipeIn.Close()
+	}
+	// kill the stages that were started when a stage could not be started
+	if startErr != nil {
+		cancel()
+	}
+
+	for _, p := range processes {
+		stage, _ := p.Wait()
+		res.Stages = append(res.Stages, stage)
+	}
+	if startErr != nil {
+		res.Stages = append(res.Stages, getStartResponse(startErr))
+		res.ExitCode = -1
+		res.Err = startErr
+		return res
+	}
+	if len(res.Stages) == 0 {
+		return res
+	}
+
+	last := res.Stages[len(res.Stages)-1]
+	res.StdOut = last.StdOut
+	res.ExitCode = last.ExitCode
+	res.Err = last.Err
+	if pl.PipeFail {
+		for i := len(res.Stages) - 1; i >= 0; i-- {
+			if res.Stages[i].ExitCode != 0 {
+				res.ExitCode = res.Stages[i].ExitCode
+				res.Err = res.Stages[i].Err
+				break
+			}
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,83 @@
+package subprocess
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPipelineUnixSortUniq(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		first := NewCommand("cat")
+		first.Stdin = InputString("b\na\nb\n")
+		response := NewPipeline(first, NewCommand("sort"), NewCommand("uniq", "-c")).Run(context.Background())
+
+		if strings.Join(strings.Fields(response.StdOut), " ") != "1 a 2 b" {
+			t.Errorf("[FAIL] Expected pipeline std out to be the counted lines and it was actually '%s'", response.StdOut)
+		}
+		if response.ExitCode != 0 || response.Err != nil {
+			t.Errorf("[FAIL] Expected pipeline to return 0 exit status code and instead it returned %d (%v)", response.ExitCode, response.Err)
+		}
+		if len(response.Stages) != 3 {
+			t.Fatalf("[FAIL] Expected a Response for 3 stages and there were %d", len(response.Stages))
+		}
+		if response.Stages[0].StdOut != "" {
+			t.Errorf("[FAIL] Expected the first stage std out to be passed to the next stage and it was '%s'", response.Stages[0].StdOut)
+		}
+	}
+}
+
+func TestPipelineUnixPipeFail(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		pipeline := NewPipeline(NewShellCommand("", "", "echo failed >&2; exit 3"), NewCommand("cat"))
+		response := pipeline.Run(context.Background())
+
+		if response.ExitCode != 0 {
+			t.Errorf("[FAIL] Expected pipeline without pipefail to return the last stage exit status code 0 and it returned %d", response.ExitCode)
+		}
+		if response.Stages[0].ExitCode != 3 || response.Stages[0].StdErr != "failed\n" {
+			t.Errorf("[FAIL] Expected first stage to return exit status code 3 with std err and it returned %d '%s'", response.Stages[0].ExitCode, response.Stages[0].StdErr)
+		}
+
+		pipeline.PipeFail = true
+		response = pipeline.Run(context.Background())
+
+		if response.ExitCode != 3 {
+			t.Errorf("[FAIL] Expected pipeline with pipefail to return exit status code 3 and it returned %d", response.ExitCode)
+		}
+		if exitError, ok := response.Err.(*ExitError); !ok || exitError.ExitCode != 3 {
+			t.Errorf("[FAIL] Expected pipeline with pipefail to return an *ExitError and instead it returned %#v", response.Err)
+		}
+	}
+}
+
+func TestPipelineUnixMissingExecutable(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		start := time.Now()
+		response := NewPipeline(NewCommand("sleep", "10"), NewCommand("bogus")).Run(context.Background())
+
+		if time.Since(start) > 5*time.Second {
+			t.Errorf("[FAIL] Expected started stages to be killed and the pipeline ran for %v", time.Since(start))
+		}
+		if _, ok := response.Err.(*LookupError); !ok {
+			t.Errorf("[FAIL] Expected missing executable to return a *LookupError and instead it returned %#v", response.Err)
+		}
+		if len(response.Stages) != 2 || response.ExitCode != -1 {
+			t.Errorf("[FAIL] Expected 2 stages and -1 exit status code and there were %d stages with %d", len(response.Stages), response.ExitCode)
+		}
+	}
+}
+
+func TestPipelineUnixTimeout(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		response := NewPipeline(NewCommand("sleep", "10"), NewCommand("cat")).Run(ctx)
+
+		if !response.Stages[0].TimedOut {
+			t.Errorf("[FAIL] Expected first stage to time out and it was %s", response.Stages[0])
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package subprocess
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Pool is a struct that is defined with the configuration for running many commands with bounded parallelism.  Define a
+// Pool with the public NewPool function.  It includes the following data fields:
+//
+//     Pool.Concurrency - (int) maximum number of commands that run at the same time.  Default (0) = number of CPUs
+//     Pool.FailFast - (bool) cancel the running commands and skip the commands that were not started yet when a command
+//                     fails (Response.Err is not nil)
+//     Pool.Ordered - (bool) deliver the results from Pool.Stream in the order of the commands instead of the order that
+//                    they complete in
+type Pool struct {
+	Concurrency int
+	FailFast    bool
+	Ordered     bool
+}
+
+// Result is a struct that is defined with the result of one command that was run by a Pool.  It includes the following
+// data fields:
+//
+//     Result.Index - (int) index of the command in the commands that were passed to the Pool
+//     Result.Command - (*Command) the command
+//     Result.Response - (Response) Response of the command
+//     Result.Skipped - (bool) the command was not started because the context was done or an earlier command failed
+//                      with Pool.FailFast.  Response.Err is the error of the context.
+type Result struct {
+	Index    int
+	Command  *Command
+	Response Response
+	Skipped  bool
+}
+
+// Summary is a struct that is defined with the aggregated results of the commands that were run by a Pool.  It is
+// returned from the public RunAll function and Pool.Run with the following data fields:
+//
+//     Summary.Results - ([]Result) Result of each command in the order of the commands
+//     Summary.Succeeded - (int) number of commands that completed with a nil Response.Err
+//     Summary.Failed - (int) number of commands that were started and failed (including the commands that were killed
+//                      by Pool.FailFast)
+//     Summary.Skipped - (int) number of commands that were not started
+//     Summary.Err - (error) Response.Err of the first command that failed, in completion order
+//     Summary.Duration - (time.Duration) wall-clock time that it took to run all of the commands
+type Summary struct {
+	Results   []Result
+	Succeeded int
+	Failed    int
+	Skipped   int
+	Err       error
+	Duration  time.Duration
+}
+
+// NewPool is a public function that returns a *Pool that runs at most concurrency commands at the same time.
+// NewPool takes the following parameters:
+//
+//  concurrency (int) - maximum number of commands that run at the same time.  0 = number of CPUs
+//
+// Example:
+//
+//     func main() {
+//         var commands []*Command
+//         for _, pkg := range []string{"./api", "./cli", "./store"} {
+//             commands = append(commands, NewCommand("go", "vet", pkg))
+//         }
+//         pool := NewPool(4)
+//         pool.FailFast = true
+//         for result := range pool.Stream(context.Background(), commands...) {
+//             fmt.Printf("%s: %s\n", result.Command.Args[1], result.Response.String())
+//         }
+//     }
+func NewPool(concurrency int) *Pool {
+	return &Pool{Concurrency: concurrency}
+}
+
+// RunAll is a public function that runs the commands with at most concurrency commands at the same time and returns the
+// aggregated Summary after all of the commands have completed.
+// RunAll takes the following parameters:
+//
+//  ctx (context.Context) - the context that bounds the execution of all of the commands
+//  concurrency (int) - maximum number of commands that run at the same time.  0 = number of CPUs
+//  commands (...*Command) - the commands to run
+//
+// Example (macOS/Linux):
+//
+//     func main() {
+//         files, _ := filepath.Glob("images/*.png")
+//         var commands []*Command
+//         for _, file := range files {
+//             commands = append(commands, NewCommand("convert", file, strings.TrimSuffix(file, ".png")+".jpg"))
+//         }
+//         summary := RunAll(context.Background(), 8, commands...)
+//         fmt.Printf("%d converted, %d failed in %v\n", summary.Succeeded, summary.Failed, summary.Duration)
+//     }
+func RunAll(ctx context.Context, concurrency int, commands ...*Command) Summary {
+	return NewPool(concurrency).Run(ctx, commands...)
+}
+
+// Run runs the commands and returns the aggregated Summary after all of the commands have completed
+func (p *Pool) Run(ctx context.Context, commands ...*Command) Summary {
+	start := time.Now()
+	summary := Summary{Results: make([]Result, len(commands))}
+	// every result is received, so the results are not dropped when the context is done
+	for result := range p.stream(ctx, nil, commands) {
+		summary.Results[result.Index] = result
+		switch {
+		case result.Skipped:
+			summary.Skipped++
+		case result.Response.Err != nil:
+			summary.Failed++
+		default:
+			summary.Succeeded++
+		}
+		if summary.Err == nil && !result.Skipped && result.Response.Err != nil {
+			summary.Err = result.Response.Err
+		}
+	}
+	summary.Duration = time.Since(start)
+
+	return summary
+}
+
+// Stream runs the commands and returns a channel that receives the Result of each command when it completes (or in the
+// order of the commands with Pool.Ordered).  The channel is closed after the last Result.  Cancel the context to stop
+// receiving before the last Result: the running commands are killed, the remaining commands are skipped, the results
+// that are not received are dropped, and the channel is closed.
+func (p *Pool) Stream(ctx context.Context, commands ...*Command) <-chan Result {
+	return p.stream(ctx, ctx.Done(), commands)
+}
+
+// stream runs the commands and delivers their results until the abandon channel is closed.  The remaining results are
+// then received from the workers and dropped so that they exit.  A nil abandon channel delivers all of the results.
+func (p *Pool) stream(ctx context.Context, abandon <-chan struct{}, commands []*Command) <-chan Result {
+	concurrency := p.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+	out := make(chan Result)
+	ctx, cancel := context.WithCancel(ctx)
+	results := make(chan Result)
+	// start the commands while fewer than concurrency commands run
+	go func() {
+		var wg sync.WaitGroup
+		slots := make(chan struct{}, concurrency)
+		for i, c := range commands {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+			}
+			if ctx.Err() != nil {
+				results <- Result{Index: i, Command: c, Skipped: true, Response: Response{ExitCode: -1, Err: ctx.Err()}}
+				continue
+			}
+			wg.Add(1)
+			go func(i int, c *Command) {
+				defer wg.Done()
+				res := c.Run(ctx)
+				// cancel before the slot is released so that no other command starts after a failure
+				if p.FailFast && res.Err != nil {
+					cancel()
+				}
+				<-slots
+				results <- Result{Index: i, Command: c, Response: res}
+			}(i, c)
+		}
+		wg.Wait()
+		close(results)
+	}()
+	// deliver the results in completion order, or hold them until the results of the earlier commands are delivered
+	go func() {
+		defer close(out)
+		defer cancel()
+		abandoned := false
+		deliver := func(r Result) {
+			if abandoned {
+				return
+			}
+			select {
+			case out <- r:
+			case <-abandon:
+				abandoned = true
+			}
+		}
+		pending := make(map[int]Result)
+		next := 0
+		for result := range results {
+			if !p.Ordered {
+				deliver(result)
+				continue
+			}
+			pending[result.Index] = result
+			for r, ok := pending[next]; ok; r, ok = pending[next] {
+				deliver(r)
+				delete(pending, next)
+				next++
+			}
+		}
+	}()
+
+	return out
+}
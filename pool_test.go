@@ -0,0 +1,95 @@
+package subprocess
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestRunAll(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		var commands []*Command
+		for i := 0; i < 6; i++ {
+			commands = append(commands, NewCommand("sleep", "0.2"))
+		}
+		commands = append(commands, NewCommand("false"))
+		summary := RunAll(context.Background(), 2, commands...)
+		if summary.Succeeded != 6 || summary.Failed != 1 || summary.Skipped != 0 || len(summary.Results) != 7 {
+			t.Errorf("[FAIL] Expected 6 succeeded and 1 failed, but received %d, %d, %d skipped", summary.Succeeded, summary.Failed, summary.Skipped)
+		}
+		if summary.Err == nil || summary.Results[6].Response.ExitCode != 1 {
+			t.Errorf("[FAIL] Expected the Summary error of the failed command, but received %v", summary.Err)
+		}
+		// 6 commands that run for 200ms with 2 at a time
+		if summary.Duration < 600*time.Millisecond || summary.Duration > 3*time.Second {
+			t.Errorf("[FAIL] Expected bounded parallelism to take about 600ms, but received %v", summary.Duration)
+		}
+	}
+}
+
+func TestPoolStreamOrder(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		commands := []*Command{NewCommand("sleep", "0.4"), NewCommand("sleep", "0.2"), NewCommand("true")}
+		var indexes []int
+		for result := range NewPool(3).Stream(context.Background(), commands...) {
+			indexes = append(indexes, result.Index)
+		}
+		if len(indexes) != 3 || indexes[0] != 2 || indexes[1] != 1 || indexes[2] != 0 {
+			t.Errorf("[FAIL] Expected the results in completion order [2 1 0], but received %v", indexes)
+		}
+		pool := NewPool(3)
+		pool.Ordered = true
+		indexes = nil
+		for result := range pool.Stream(context.Background(), commands...) {
+			indexes = append(indexes, result.Index)
+		}
+		if len(indexes) != 3 || indexes[0] != 0 || indexes[1] != 1 || indexes[2] != 2 {
+			t.Errorf("[FAIL] Expected the results in input order [0 1 2], but received %v", indexes)
+		}
+	}
+}
+
+func TestPoolFailFast(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		commands := []*Command{NewCommand("sleep", "10"), NewCommand("false")}
+		for i := 0; i < 5; i++ {
+			commands = append(commands, NewCommand("sleep", "10"))
+		}
+		pool := NewPool(2)
+		pool.FailFast = true
+		start := time.Now()
+		summary := pool.Run(context.Background(), commands...)
+		if time.Since(start) > 5*time.Second {
+			t.Errorf("[FAIL] Expected fail-fast to cancel the running commands, but the pool ran for %v", time.Since(start))
+		}
+		if summary.Failed != 2 || summary.Skipped != 5 || summary.Succeeded != 0 {
+			t.Errorf("[FAIL] Expected 2 failed and 5 skipped, but received %d failed and %d skipped", summary.Failed, summary.Skipped)
+		}
+		if !summary.Results[0].Response.Canceled || !summary.Results[6].Skipped {
+			t.Errorf("[FAIL] Expected the running command to be canceled and the last command to be skipped")
+		}
+	}
+}
+
+func TestPoolStreamCanceled(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		before := runtime.NumGoroutine()
+		var commands []*Command
+		for i := 0; i < 4; i++ {
+			commands = append(commands, NewCommand("sleep", "0.1"))
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		// the consumer stops receiving after the first result
+		<-NewPool(2).Stream(ctx, commands...)
+		cancel()
+
+		deadline := time.Now().Add(5 * time.Second)
+		for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+			time.Sleep(20 * time.Millisecond)
+		}
+		if after := runtime.NumGoroutine(); after > before {
+			t.Errorf("[FAIL] Expected the pool goroutines to exit after the context was canceled, but %d of them remain", after-before)
+		}
+	}
+}
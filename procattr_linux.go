@@ -0,0 +1,11 @@
+package subprocess
+
+import (
+	"syscall"
+)
+
+// setPdeathsig defines the signal that the process receives when the parent process dies
+func setPdeathsig(attr *syscall.SysProcAttr, sig syscall.Signal) error {
+	attr.Pdeathsig = sig
+	return nil
+}
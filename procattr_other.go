@@ -0,0 +1,16 @@
+//go:build unix && !linux
+
+package subprocess
+
+import (
+	"errors"
+	"syscall"
+)
+
+// setPdeathsig returns an error because a parent death signal is only supported on Linux
+func setPdeathsig(attr *syscall.SysProcAttr, sig syscall.Signal) error {
+	if sig != 0 {
+		return errors.New("subprocess: Pdeathsig is only supported on Linux")
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+//go:build unix
+
+package subprocess
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcAttr defines the process group, session, and parent death signal attributes of a Command process
+func setProcAttr(cmd *exec.Cmd, c *Command) error {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	// a new session also creates a new process group.  Setpgid and Setsid cannot be combined.
+	if c.Session {
+		cmd.SysProcAttr.Setsid = true
+	} else if c.ProcessGroup {
+		cmd.SysProcAttr.Setpgid = true
+	}
+
+	return setPdeathsig(cmd.SysProcAttr, c.Pdeathsig)
+}
+
+// signalProcess sends a signal to a process, or to every process in the process group of the process when group is
+// true.  The process must be the leader of its process group.
+func signalProcess(p *os.Process, group bool, sig syscall.Signal) error {
+	if group {
+		// a negative pid signals the process group
+		return syscall.Kill(-p.Pid, sig)
+	}
+	return p.Signal(sig)
+}
@@ -0,0 +1,37 @@
+package subprocess
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcAttr defines the process group and command line attributes of a Command process.  Sessions and parent death
+// signals are not supported on Windows.
+func setProcAttr(cmd *exec.Cmd, c *Command) error {
+	if c.Session {
+		return errors.New("subprocess: Session is not supported on Windows")
+	}
+	if c.Pdeathsig != 0 {
+		return errors.New("subprocess: Pdeathsig is only supported on Linux")
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	if c.ProcessGroup {
+		cmd.SysProcAttr.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP
+	}
+	// the raw command line of a cmd.exe command with quoted arguments
+	if c.cmdLine != "" {
+		cmd.SysProcAttr.CmdLine = syscall.EscapeArg(c.Path) + " /S " + c.cmdLine
+	}
+
+	return nil
+}
+
+// signalProcess kills a process.  Windows does not support signals other than kill, and the processes that were
+// started by the process are not killed.
+func signalProcess(p *os.Process, group bool, sig syscall.Signal) error {
+	return p.Kill()
+}
@@ -0,0 +1,212 @@
+package subprocess
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// Process is a handle to a running Command process that was started with the public Start and StartShell functions or
+// the Command.Start method.  The standard output and standard error stream data are collected in the background with
+// the same behavior as Command.Run.
+type Process struct {
+	ctx      context.Context
+	cmd      *exec.Cmd
+	group    bool
+	term     *terminator
+	stdout   *captureBuffer
+	stderr   *captureBuffer
+	outlines *lineWriter
+	errlines *lineWriter
+	stdin    *os.File
+	cleanup  []func()
+	done     chan struct{}
+	res      Response
+
+	pty       *ptyState
+	stripANSI bool
+	limits    map[Resource]uint64
+	cgroup    *cgroup
+
+	hook  Hook
+	event StartEvent
+}
+
+// Start is a public function that starts a system command and returns a *Process handle without waiting for the
+// command to complete.  A *LookupError or *StartError is returned when the process could not be started.
+// Start takes the following parameters:
+//
+//  ctx (context.Context) - the context that bounds the execution of the command
+//  executable (string) - the executable for the command
+//  args (...string) - one or more arguments to the executable as a comma-delimited list of parameters
+//
+// Example:
+//
+//     func main() {
+//         process, err := Start(context.Background(), "python3", "-m", "http.server", "8000")
+//         if err != nil {
+//             log.Fatal(err)
+//         }
+//         fmt.Printf("started server with pid %d\n", process.PID())
+//         // ... run tests against the server
+//         process.Signal(os.Interrupt)
+//         response, _ := process.Wait()
+//         fmt.Printf("%s\n", response.StdErr)
+//     }
+func Start(ctx context.Context, executable string, args ...string) (*Process, error) {
+	return NewCommand(executable, args...).Start(ctx)
+}
+
+// StartShell is a public function that starts a system command with a shell and returns a *Process handle without
+// waiting for the command to complete.  The parameters are defined with the same defaults as the public RunShell
+// function.
+// StartShell takes the following parameters:
+//
+//  ctx (context.Context) - the context that bounds the execution of the command
+//  shell (string) - path to the shell.  Defaults = /bin/sh on Linux, macOS; cmd.exe on Windows
+//  shellflag (string) - flag to run executable file with shell. Default = `-c` (macOS/Linux); `/C` (Win)
+//  command (...string) - one or more executable commands, comma-delimited parameter format
+func StartShell(ctx context.Context, shell string, shellflag string, command ...string) (*Process, error) {
+	return NewShellCommand(shell, shellflag, command...).Start(ctx)
+}
+
+// PID returns the process ID of the process
+func (p *Process) PID() int {
+	return p.cmd.Process.Pid
+}
+
+// Signal sends a signal to the process.  The signal is sent to the whole process group when the command was started
+// with Command.ProcessGroup or Command.Session on macOS/Linux.
+func (p *Process) Signal(sig os.Signal) error {
+	if s, ok := sig.(syscall.Signal); ok && p.group && runtime.GOOS != "windows" {
+		return signalProcess(p.cmd.Process, true, s)
+	}
+	return p.cmd.Process.Signal(sig)
+}
+
+// Stdin returns the writer for the standard input stream of a process that was started with the InputPipe Input.  Close
+// the writer to signal the end of the input.  nil is returned for other Input types.
+func (p *Process) Stdin() io.WriteCloser {
+	if p.stdin == nil {
+		return nil
+	}
+	return p.stdin
+}
+
+// Done returns a channel that is closed when the process has completed and its Response is available from Wait
+func (p *Process) Done() <-chan struct{} {
+	return p.done
+}
+
+// Wait waits for the process to complete and returns its Response.  The returned error is Response.Err.  Wait may be
+// called more than once and from multiple goroutines.
+func (p *Process) Wait() (Response, error) {
+	<-p.done
+	return p.res, p.res.Err
+}
+
+// StdOut returns the standard output stream data that has been read from the process so far with the limits of
+// Command.StdOutCapture.  It is empty when Stream.Discard is set.
+func (p *Process) StdOut() string {
+	return p.stdout.String()
+}
+
+// StdErr returns the standard error stream data that has been read from the process so far with the limits of
+// Command.StdErrCapture.  It is empty when Stream.Discard is set.
+func (p *Process) StdErr() string {
+	return p.stderr.String()
+}
+
+// newProcess returns a *Process for a system command that has not been started and defines the standard output and
+// standard error writers of the command with the capture limits
+func newProcess(ctx context.Context, cmd *exec.Cmd, stream Stream, outcap Capture, errcap Capture) *Process {
+	p := &Process{ctx: ctx, cmd: cmd, done: make(chan struct{})}
+	if stream.Discard {
+		// no data is held but the number of bytes is still counted
+		outcap = Capture{Policy: CaptureHead}
+		errcap = Capture{Policy: CaptureHead}
+	}
+	p.stdout = newCaptureBuffer(outcap)
+	p.stderr = newCaptureBuffer(errcap)
+	cmd.Stdout, p.outlines = getStreamWriter(p.stdout, stream.StdOutLine, stream.StdOutChunk)
+	cmd.Stderr, p.errlines = getStreamWriter(p.stderr, stream.StdErrLine, stream.StdErrChunk)
+
+	return p
+}
+
+// release runs the cleanup functions of the process in reverse order
+func (p *Process) release() {
+	for i := len(p.cleanup) - 1; i >= 0; i-- {
+		p.cleanup[i]()
+	}
+}
+
+// wait waits for the system command to complete, defines the Response of the process, and closes the done channel
+func (p *Process) wait() {
+	defer close(p.done)
+	defer p.release()
+	res := Response{StartTime: p.res.StartTime}
+	cmd := p.cmd
+
+	// the stops of the process are only reported to a wait before exec.Cmd reaps it
+	res.Stopped, res.StopSignal = p.waitStops()
+	err := cmd.Wait()
+	res.EndTime = time.Now()
+	res.Duration = res.EndTime.Sub(res.StartTime)
+	// the pseudo-terminal data is copied in the current process after the process exits
+	p.waitPTY(cmd.WaitDelay)
+	// pass the final line of the stream data that does not end with a line ending to the line handlers
+	if p.outlines != nil {
+		p.outlines.flush()
+	}
+	if p.errlines != nil {
+		p.errlines.flush()
+	}
+	// define the returned object fields with the data returned
+	res.StdOut = p.stdout.String()
+	res.StdErr = p.stderr.String()
+	if p.stripANSI {
+		res.StdOut = StripANSI(res.StdOut)
+	}
+	res.StdOutBytes, res.StdOutTruncated, res.StdOutFile = p.stdout.result()
+	res.StdErrBytes, res.StdErrTruncated, res.StdErrFile = p.stderr.result()
+	if err == nil {
+		// report the CaptureSpill temporary file errors when the process completed without an error
+		if err = p.stdout.close(); err == nil {
+			err = p.stderr.close()
+		}
+	} else {
+		p.stdout.close()
+		p.stderr.close()
+	}
+	if err != nil {
+		res.ExitCode = getErrorExitCode(err)
+		res.Err = getWaitError(err)
+	}
+	// the exit status code is also known when the streams were closed after Command.WaitDelay (exec.ErrWaitDelay)
+	if cmd.ProcessState != nil {
+		res.ExitCode = cmd.ProcessState.Sys().(syscall.WaitStatus).ExitStatus()
+		setWaitStatus(&res, cmd.ProcessState.Sys().(syscall.WaitStatus))
+		res.Usage = getUsage(cmd.ProcessState)
+		res.LimitExceeded = limitExceeded(res, p.limits)
+	}
+	if p.cgroup != nil {
+		p.cgroup.result(&res)
+	}
+	res.Termination = p.term.stop()
+	// the process was killed when the context was done before the command completed.  A process that handles the
+	// termination signal may exit with a zero exit status code.
+	if (err != nil || res.Termination != TerminationNone) && p.ctx.Err() != nil {
+		res.TimedOut = p.ctx.Err() == context.DeadlineExceeded
+		res.Canceled = p.ctx.Err() == context.Canceled
+	}
+
+	p.res = res
+	if p.hook != nil {
+		p.hook.Finished(p.ctx, newFinishEvent(p.event, res))
+	}
+}
@@ -0,0 +1,105 @@
+package subprocess
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestStartCliMockWait(t *testing.T) {
+	process, err := Start(context.Background(), "climock", "--stdout", "This is a test", "--exit", "2")
+	if err != nil {
+		t.Fatalf("[FAIL] Expected mock process to start and instead it returned %v", err)
+	}
+	if process.PID() <= 0 {
+		t.Errorf("[FAIL] Expected a process ID and it was %d", process.PID())
+	}
+	response, err := process.Wait()
+
+	if response.ExitCode != 2 {
+		t.Errorf("[FAIL] Expected mock exit code to be 2 and it was %d", response.ExitCode)
+	}
+	if response.StdOut != "This is a test" {
+		t.Errorf("[FAIL] Expected mock std out to be 'This is a test' and it was actually '%s'", response.StdOut)
+	}
+	if _, ok := err.(*ExitError); !ok {
+		t.Errorf("[FAIL] Expected Wait to return an *ExitError and instead it returned %#v", err)
+	}
+	select {
+	case <-process.Done():
+	default:
+		t.Errorf("[FAIL] Expected Done channel to be closed after Wait returned")
+	}
+}
+
+func TestStartMissingExecutable(t *testing.T) {
+	process, err := Start(context.Background(), "bogus")
+
+	if _, ok := err.(*LookupError); !ok {
+		t.Errorf("[FAIL] Expected missing executable to return a *LookupError and instead it returned %#v", err)
+	}
+	if process != nil {
+		t.Errorf("[FAIL] Expected missing executable to return a nil *Process")
+	}
+}
+
+func TestStartShellUnixLiveOutputSignal(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		process, err := StartShell(context.Background(), "", "", "echo ready; exec sleep 10")
+		if err != nil {
+			t.Fatalf("[FAIL] Expected shell process to start and instead it returned %v", err)
+		}
+		deadline := time.Now().Add(5 * time.Second)
+		for process.StdOut() != "ready\n" && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if process.StdOut() != "ready\n" {
+			t.Errorf("[FAIL] Expected live std out to be 'ready' while the process was running and it was '%s'", process.StdOut())
+		}
+		select {
+		case <-process.Done():
+			t.Errorf("[FAIL] Expected Done channel to be open while the process was running")
+		default:
+		}
+		if err := process.Signal(syscall.SIGTERM); err != nil {
+			t.Errorf("[FAIL] Expected signal to be sent and instead it returned %v", err)
+		}
+		select {
+		case <-process.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatalf("[FAIL] Expected Done channel to be closed after the process was signaled")
+		}
+		response, _ := process.Wait()
+
+		if !response.Signaled || response.Signal != syscall.SIGTERM {
+			t.Errorf("[FAIL] Expected process to be killed by SIGTERM and it was %s", response)
+		}
+		if response.StdOut != "ready\n" {
+			t.Errorf("[FAIL] Expected std out to be 'ready' and it was actually '%s'", response.StdOut)
+		}
+	}
+}
+
+func TestCommandStartUnixProcessGroupKill(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		cmd := NewShellCommand("", "", "sleep 10 & sleep 10")
+		cmd.ProcessGroup = true
+		process, err := cmd.Start(context.Background())
+		if err != nil {
+			t.Fatalf("[FAIL] Expected shell process to start and instead it returned %v", err)
+		}
+		start := time.Now()
+		process.Signal(os.Kill)
+		response, _ := process.Wait()
+
+		if time.Since(start) > 5*time.Second {
+			t.Errorf("[FAIL] Expected process group to be killed and the process ran for %v", time.Since(start))
+		}
+		if !response.Signaled || response.Signal != syscall.SIGKILL {
+			t.Errorf("[FAIL] Expected process to be killed by SIGKILL and it was %s", response)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package subprocess
+
+import (
+	"regexp"
+)
+
+// PTY is a struct that is defined with the pseudo-terminal configuration of a Command.  The process runs with its
+// standard input, standard output, and standard error streams connected to a pseudo-terminal so that it behaves as it
+// does in an interactive terminal (e.g. colors and progress bars).  PTY is supported on Linux and macOS.  It includes
+// the following data fields:
+//
+//     PTY.Rows - (uint16) number of rows of the terminal window.  Default = 24
+//     PTY.Cols - (uint16) number of columns of the terminal window.  Default = 80
+//     PTY.StripANSI - (bool) remove ANSI escape sequences from Response.StdOut
+//
+// The standard output and standard error stream data are combined in Response.StdOut and the terminal translates line
+// endings to "\r\n".  Command.Stdin is written to the terminal followed by an end-of-file character.
+type PTY struct {
+	Rows      uint16
+	Cols      uint16
+	StripANSI bool
+}
+
+// size returns the number of rows and columns of the terminal window with the defaults for undefined values
+func (t *PTY) size() (uint16, uint16) {
+	rows, cols := t.Rows, t.Cols
+	if rows == 0 {
+		rows = 24
+	}
+	if cols == 0 {
+		cols = 80
+	}
+	return rows, cols
+}
+
+// ansiPattern matches ANSI CSI and OSC escape sequences and two character escape sequences
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-?]*[ -/]*[@-~]|\x1b\\][^\x07\x1b]*(?:\x07|\x1b\\\\)|\x1b[@-Z\\\\-_]")
+
+// StripANSI returns the string with the ANSI escape sequences (colors, cursor movement, terminal titles) removed
+func StripANSI(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
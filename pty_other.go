@@ -0,0 +1,27 @@
+//go:build !linux && !darwin
+
+package subprocess
+
+import (
+	"errors"
+	"io"
+	"os/exec"
+	"time"
+)
+
+// ptyState is the pseudo-terminal of a Process.  Pseudo-terminals are not supported on this platform.
+type ptyState struct{}
+
+// setPTY returns an error because pseudo-terminals are only supported on Linux and macOS
+func (p *Process) setPTY(cmd *exec.Cmd, t *PTY, stdin io.Reader) error {
+	return errors.New("subprocess: PTY is only supported on Linux and macOS")
+}
+
+func (p *Process) startPTY(started bool) {}
+
+func (p *Process) waitPTY(delay time.Duration) {}
+
+// Resize returns an error because pseudo-terminals are only supported on Linux and macOS
+func (p *Process) Resize(rows uint16, cols uint16) error {
+	return errors.New("subprocess: PTY is only supported on Linux and macOS")
+}
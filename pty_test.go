@@ -0,0 +1,75 @@
+package subprocess
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStripANSI(t *testing.T) {
+	s := StripANSI("\x1b[1;31mred\x1b[0m \x1b]0;title\x07text\x1b[2K")
+	if s != "red text" {
+		t.Errorf("[FAIL] Expected StripANSI to return 'red text', but received '%s'", s)
+	}
+}
+
+func TestCommandPTY(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		return
+	}
+	cmd := NewShellCommand("", "", "test -t 0 && test -t 1 && echo tty; stty size")
+	cmd.PTY = &PTY{Rows: 30, Cols: 100}
+	response := cmd.Run(context.Background())
+	if response.ExitCode != 0 {
+		t.Errorf("[FAIL] Expected exit code 0 with a PTY, but received %d (%v)", response.ExitCode, response.Err)
+	}
+	if response.StdOut != "tty\r\n30 100\r\n" {
+		t.Errorf("[FAIL] Expected the process to run in a 30x100 terminal, but received '%q'", response.StdOut)
+	}
+}
+
+func TestCommandPTYStdinAndStripANSI(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		return
+	}
+	cmd := NewShellCommand("", "", "stty -echo; read line; printf '\\033[32m%s\\033[0m\\n' \"$line\"")
+	cmd.PTY = &PTY{StripANSI: true}
+	cmd.Stdin = InputString("hello\n")
+	response := cmd.Run(context.Background())
+	if !strings.HasSuffix(response.StdOut, "hello\r\n") || strings.Contains(response.StdOut, "\x1b") {
+		t.Errorf("[FAIL] Expected stripped PTY output 'hello', but received '%q'", response.StdOut)
+	}
+}
+
+func TestCommandPTYStdinWithoutNewline(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		return
+	}
+	cmd := NewCommand("cat")
+	cmd.PTY = &PTY{}
+	cmd.Stdin = InputString("abc")
+	cmd.Timeout = 5 * time.Second
+	response := cmd.Run(context.Background())
+	if response.TimedOut || response.ExitCode != 0 {
+		t.Errorf("[FAIL] Expected cat to exit at the end of input without a newline, but received %s", response)
+	}
+	if !strings.Contains(response.StdOut, "abc") {
+		t.Errorf("[FAIL] Expected the PTY output to contain 'abc', but received '%q'", response.StdOut)
+	}
+}
+
+func TestProcessResize(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		return
+	}
+	p, err := Start(context.Background(), "sh", "-c", "exit 0")
+	if err != nil {
+		t.Fatalf("[FAIL] Expected the process to start, but received error %v", err)
+	}
+	if err := p.Resize(10, 10); err == nil {
+		t.Errorf("[FAIL] Expected a Resize error for a process without a PTY")
+	}
+	p.Wait()
+}
@@ -0,0 +1,151 @@
+//go:build linux || darwin
+
+package subprocess
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// ptyState is the pseudo-terminal of a Process
+type ptyState struct {
+	master *os.File
+	slave  *os.File
+	stdin  io.Reader
+	output io.Writer
+	copied sync.WaitGroup
+}
+
+// setPTY opens a pseudo-terminal and connects it to the standard streams of the command.  The terminal data is copied
+// to the standard output writer of the command.  The process is started in a new session with the pseudo-terminal as
+// its controlling terminal.
+func (p *Process) setPTY(cmd *exec.Cmd, t *PTY, stdin io.Reader) error {
+	master, slave, err := openPTY()
+	if err != nil {
+		return err
+	}
+	p.pty = &ptyState{master: master, slave: slave, stdin: stdin, output: cmd.Stdout}
+	p.stripANSI = t.StripANSI
+	p.group = true
+	if err := p.Resize(t.size()); err != nil {
+		master.Close()
+		slave.Close()
+		return err
+	}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	// a session leader cannot change its process group
+	cmd.SysProcAttr.Setpgid = false
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+	cmd.SysProcAttr.Ctty = 0 // the standard input stream of the process
+
+	return nil
+}
+
+// startPTY closes the copy of the pseudo-terminal slave in the current process after the process was started (or
+// failed to start) and copies the pseudo-terminal data while the process runs
+func (p *Process) startPTY(started bool) {
+	if p.pty == nil {
+		return
+	}
+	p.pty.slave.Close()
+	if !started {
+		p.pty.master.Close()
+		return
+	}
+	p.pty.copied.Add(1)
+	go func() {
+		defer p.pty.copied.Done()
+		// the read returns EIO on Linux when the slave is closed by all processes
+		io.Copy(p.pty.output, p.pty.master)
+	}()
+	if p.pty.stdin != nil {
+		go func() {
+			last := &lastByteWriter{w: p.pty.master}
+			io.Copy(last, p.pty.stdin)
+			// the end-of-file character (^D) only flushes a partial line in canonical mode.  A second ^D ends the input.
+			if last.n > 0 && last.b != '\n' {
+				p.pty.master.Write([]byte{4})
+			}
+			p.pty.master.Write([]byte{4})
+		}()
+	}
+}
+
+// lastByteWriter is a writer that keeps the last byte that was written to the underlying writer
+type lastByteWriter struct {
+	w io.Writer
+	b byte
+	n int64
+}
+
+func (l *lastByteWriter) Write(p []byte) (int, error) {
+	n, err := l.w.Write(p)
+	if n > 0 {
+		l.b = p[n-1]
+		l.n += int64(n)
+	}
+	return n, err
+}
+
+// waitPTY waits for the pseudo-terminal data to be copied after the process exits and closes the pseudo-terminal.
+// The pseudo-terminal is closed after the delay when processes that were started by the process hold it open.
+func (p *Process) waitPTY(delay time.Duration) {
+	if p.pty == nil {
+		return
+	}
+	copied := make(chan struct{})
+	go func() {
+		p.pty.copied.Wait()
+		close(copied)
+	}()
+	if delay > 0 {
+		select {
+		case <-copied:
+		case <-time.After(delay):
+		}
+	} else {
+		<-copied
+	}
+	p.pty.master.Close()
+	<-copied
+}
+
+// Resize sets the number of rows and columns of the terminal window of a process that was started with Command.PTY
+func (p *Process) Resize(rows uint16, cols uint16) error {
+	if p.pty == nil {
+		return errors.New("subprocess: the process was not started with a pseudo-terminal")
+	}
+	ws := struct{ rows, cols, x, y uint16 }{rows, cols, 0, 0}
+	return ioctl(p.pty.master, syscall.TIOCSWINSZ, unsafe.Pointer(&ws))
+}
+
+// ioctl calls the ioctl system call on the file descriptor of the file without a change of its blocking mode.  The
+// argument is converted to a uintptr in the system call expression, so that the memory it points to is kept alive and
+// is not moved before the call.
+func ioctl(f *os.File, req uint, arg unsafe.Pointer) error {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var errno syscall.Errno
+	err = conn.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(req), uintptr(arg))
+	})
+	if err != nil {
+		return err
+	}
+	if errno != 0 {
+		return os.NewSyscallError("ioctl", errno)
+	}
+	return nil
+}
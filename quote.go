@@ -0,0 +1,147 @@
+package subprocess
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+)
+
+// QuotePOSIX returns the argument quoted for a POSIX shell (sh, bash, zsh).  The shell passes the quoted argument to
+// the executable as one literal argument without the expansion of variables, globs, or other shell syntax.
+//
+// Example:
+//
+//     QuotePOSIX("it's $HOME") // returns 'it'\''s $HOME'
+func QuotePOSIX(arg string) string {
+	if arg == "" {
+		return "''"
+	}
+	if strings.IndexFunc(arg, isUnsafePOSIX) < 0 {
+		return arg
+	}
+
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// isUnsafePOSIX returns true for the characters that must be quoted in a POSIX shell argument
+func isUnsafePOSIX(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return false
+	case strings.ContainsRune("@%+=:,./-_", r):
+		return false
+	}
+	return true
+}
+
+// QuoteCmd returns the argument quoted for the Windows cmd.exe command prompt.  The argument is quoted with the
+// CommandLineToArgvW rules that are used by most Windows executables and the cmd.exe metacharacters are escaped with ^
+// so that cmd.exe passes the argument to the executable without the expansion of variables or redirection.
+//
+// Example:
+//
+//     QuoteCmd(`a "b" & %PATH%`) // returns ^"a \^"b\^" ^& ^%PATH^%^"
+func QuoteCmd(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\n\v\"&|<>()^%!,;=") {
+		return arg
+	}
+	// CommandLineToArgvW quoting: backslashes are literal unless they precede a double quote
+	var b strings.Builder
+	b.WriteByte('"')
+	backslashes := 0
+	for _, r := range arg {
+		switch r {
+		case '\\':
+			backslashes++
+			continue
+		case '"':
+			b.WriteString(strings.Repeat(`\`, 2*backslashes+1))
+		default:
+			b.WriteString(strings.Repeat(`\`, backslashes))
+		}
+		backslashes = 0
+		b.WriteRune(r)
+	}
+	b.WriteString(strings.Repeat(`\`, 2*backslashes))
+	b.WriteByte('"')
+	// cmd.exe escaping of the quoted argument
+	var escaped strings.Builder
+	for _, r := range b.String() {
+		if strings.ContainsRune(`"&|<>()^%!`, r) {
+			escaped.WriteByte('^')
+		}
+		escaped.WriteRune(r)
+	}
+
+	return escaped.String()
+}
+
+// QuoteArgs returns the arguments quoted for the shell and joined with spaces.  QuoteCmd is used for the cmd.exe
+// shell and QuotePOSIX is used for all other shells.  The shell is defined with the same defaults as the public
+// RunShell function.
+func QuoteArgs(shell string, args ...string) string {
+	shell, _ = getShell(shell, "")
+	quote := QuotePOSIX
+	if isCmdShell(shell) {
+		quote = QuoteCmd
+	}
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = quote(arg)
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// isCmdShell returns true when the shell is the Windows cmd.exe command prompt
+func isCmdShell(shell string) bool {
+	name := strings.ToLower(filepath.Base(strings.ReplaceAll(shell, `\`, "/")))
+	return name == "cmd" || name == "cmd.exe"
+}
+
+// RunShellArgs is a public function that executes a system command with a shell with the same behavior as RunShell.
+// The command parameter is interpreted by the shell.  Each of the args parameters is quoted for the shell with
+// QuoteArgs and passed to the command as one literal argument.
+// RunShellArgs takes the following parameters:
+//
+//  shell (string) - path to the shell.  Defaults = /bin/sh on Linux, macOS; cmd.exe on Windows
+//  shellflag (string) - flag to run executable file with shell. Default = `-c` (macOS/Linux); `/C` (Win)
+//  command (string) - the command that is interpreted by the shell
+//  args (...string) - one or more literal arguments to the command as a comma-delimited list of parameters
+//
+// Example (macOS/Linux):
+//
+//     func main() {
+//         // the file name is not interpreted by the shell
+//         response := RunShellArgs("", "", "grep -c TODO", "notes; rm -rf ~.txt")
+//         fmt.Printf("%s\n", response.StdOut)
+//     }
+func RunShellArgs(shell string, shellflag string, command string, args ...string) Response {
+	return NewShellCommandArgs(shell, shellflag, command, args...).Run(context.Background())
+}
+
+// NewShellCommandArgs is a public function that returns a *Command that executes a system command with a shell.  The
+// command parameter is interpreted by the shell.  Each of the args parameters is quoted for the shell with QuoteArgs
+// and passed to the command as one literal argument.  The parameters are defined with the same defaults as the public
+// RunShell function.
+// NewShellCommandArgs takes the following parameters:
+//
+//  shell (string) - path to the shell.  Defaults = /bin/sh on Linux, macOS; cmd.exe on Windows
+//  shellflag (string) - flag to run executable file with shell. Default = `-c` (macOS/Linux); `/C` (Win)
+//  command (string) - the command that is interpreted by the shell
+//  args (...string) - one or more literal arguments to the command as a comma-delimited list of parameters
+func NewShellCommandArgs(shell string, shellflag string, command string, args ...string) *Command {
+	shell, shellflag = getShell(shell, shellflag)
+	shellExecString := command
+	if len(args) > 0 {
+		shellExecString += " " + QuoteArgs(shell, args...)
+	}
+	c := NewCommand(shell, shellflag, shellExecString)
+	// cmd.exe does not parse the command line with the CommandLineToArgvW rules.  The command line is passed to cmd.exe
+	// without quoting and /S removes the quotes around the command.
+	if isCmdShell(shell) {
+		c.cmdLine = shellflag + ` "` + shellExecString + `"`
+	}
+
+	return c
+}
@@ -0,0 +1,73 @@
+package subprocess
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestQuotePOSIX(t *testing.T) {
+	tests := map[string]string{
+		"":              "''",
+		"simple":        "simple",
+		"path/to/file":  "path/to/file",
+		"two words":     "'two words'",
+		"it's":          `'it'\''s'`,
+		"$HOME":         "'$HOME'",
+		"a;rm -rf ~":    "'a;rm -rf ~'",
+		"`whoami` *.go": "'`whoami` *.go'",
+	}
+	for arg, expected := range tests {
+		if QuotePOSIX(arg) != expected {
+			t.Errorf("[FAIL] Expected '%s' to be quoted as %s and it was %s", arg, expected, QuotePOSIX(arg))
+		}
+	}
+}
+
+func TestQuoteCmd(t *testing.T) {
+	tests := map[string]string{
+		"":                `^"^"`,
+		"simple":          "simple",
+		`C:\path\to\file`: `C:\path\to\file`,
+		"two words":       `^"two words^"`,
+		`a "b" & %PATH%`:  `^"a \^"b\^" ^& ^%PATH^%^"`,
+		`trailing\ dir\`:  `^"trailing\ dir\\^"`,
+	}
+	for arg, expected := range tests {
+		if QuoteCmd(arg) != expected {
+			t.Errorf("[FAIL] Expected '%s' to be quoted as %s and it was %s", arg, expected, QuoteCmd(arg))
+		}
+	}
+}
+
+func TestQuoteArgs(t *testing.T) {
+	if QuoteArgs("/bin/bash", "a b", "c") != "'a b' c" {
+		t.Errorf("[FAIL] Expected POSIX shell arguments to be quoted and they were %s", QuoteArgs("/bin/bash", "a b", "c"))
+	}
+	if QuoteArgs(`C:\Windows\System32\cmd.exe`, "a b", "c") != `^"a b^" c` {
+		t.Errorf("[FAIL] Expected cmd.exe arguments to be quoted and they were %s", QuoteArgs(`C:\Windows\System32\cmd.exe`, "a b", "c"))
+	}
+}
+
+func TestRunShellArgsUnixLiteralArguments(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		response := RunShellArgs("", "", `printf '%s\n'`, "two words", "it's", "$HOME", "a;echo injected", "*", "")
+
+		expected := "two words\nit's\n$HOME\na;echo injected\n*\n\n"
+		if response.StdOut != expected {
+			t.Errorf("[FAIL] Expected arguments to be passed literally as '%s' and they were '%s'", expected, response.StdOut)
+		}
+		if response.ExitCode != 0 {
+			t.Errorf("[FAIL] Expected command to return 0 exit status code and instead it returned %d", response.ExitCode)
+		}
+	}
+}
+
+func TestRunShellArgsWindowsLiteralArguments(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		response := RunShellArgs("", "", "echo", "a & echo injected")
+
+		if response.StdOut != "\"a & echo injected\"\r\n" {
+			t.Errorf("[FAIL] Expected argument to be passed literally and it was '%s'", response.StdOut)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package subprocess
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"regexp"
+	"slices"
+	"syscall"
+	"time"
+)
+
+// Retry is a struct that is defined with the retry policy of a command that fails now and then (e.g. commands that
+// use the network).  It is used with the public RunRetry and RunShellRetry functions and the Command.RunRetry method
+// and includes the following data fields:
+//
+//     Retry.Attempts - (int) maximum number of attempts, including the first.  Default = 3
+//     Retry.Delay - (time.Duration) backoff delay before the second attempt.  Default (0) = no delay
+//     Retry.MaxDelay - (time.Duration) maximum backoff delay.  Default (0) = no maximum
+//     Retry.Multiplier - (float64) factor that the backoff delay grows by after each attempt.  Default = 2
+//     Retry.Jitter - (float64) fraction of the backoff delay that is randomized, from 0 to 1.  e.g. 0.2 = ±20%.  A delay
+//                    capped by MaxDelay is randomized within [MaxDelay*(1-Jitter), MaxDelay]
+//     Retry.RetryIf - (func(Response) bool) decides whether the Response of an attempt is retried.  Default = retry
+//                     when Response.Err is not nil and the executable was found
+//
+// Use the RetryOnExitCodes, RetryOnStdErr, and RetryOnSignals functions for common RetryIf predicates.
+type Retry struct {
+	Attempts   int
+	Delay      time.Duration
+	MaxDelay   time.Duration
+	Multiplier float64
+	Jitter     float64
+	RetryIf    func(Response) bool
+}
+
+// RetryResponse is a struct that is defined with data on the execution of a command with a Retry policy.  It is
+// returned from the public RunRetry and RunShellRetry functions with the following data fields:
+//
+//     RetryResponse.Response - (Response) Response of the last attempt.  Its fields are promoted to RetryResponse
+//     RetryResponse.Attempts - ([]Response) Response of each attempt, in order
+type RetryResponse struct {
+	Response
+	Attempts []Response
+}
+
+// RunRetry is a public function that executes a system command with the same behavior as RunContext and runs it again
+// with a backoff delay while the Retry policy decides that the Response is retried.  No attempts are started after the
+// context is done.
+// RunRetry takes the following parameters:
+//
+//  ctx (context.Context) - the context that bounds all of the attempts and the backoff delays
+//  retry (Retry) - the retry policy
+//  executable (string) - the executable for the command
+//  args (...string) - one or more arguments to the executable as a comma-delimited list of parameters
+//
+// Example:
+//
+//     func main() {
+//         retry := Retry{Attempts: 5, Delay: time.Second, MaxDelay: 30 * time.Second, Jitter: 0.2}
+//         response := RunRetry(context.Background(), retry, "git", "fetch", "origin")
+//         fmt.Printf("%d attempts\n", len(response.Attempts))
+//         fmt.Printf("%d\n", response.ExitCode)
+//     }
+func RunRetry(ctx context.Context, retry Retry, executable string, args ...string) RetryResponse {
+	return NewCommand(executable, args...).RunRetry(ctx, retry)
+}
+
+// RunShellRetry is a public function that executes a system command with a shell with the same behavior as
+// RunShellContext and runs it again with a backoff delay while the Retry policy decides that the Response is retried.
+// RunShellRetry takes the following parameters:
+//
+//  ctx (context.Context) - the context that bounds all of the attempts and the backoff delays
+//  retry (Retry) - the retry policy
+//  shell (string) - path to the shell.  Defaults = /bin/sh on Linux, macOS; cmd.exe on Windows
+//  shellflag (string) - flag to run executable file with shell. Default = `-c` (macOS/Linux); `/C` (Win)
+//  command (...string) - one or more executable commands, comma-delimited parameter format
+//
+// Example (macOS/Linux):
+//
+//     func main() {
+//         retry := Retry{Delay: 2 * time.Second, RetryIf: RetryOnStdErr(regexp.MustCompile(`(?i)timed? ?out`))}
+//         response := RunShellRetry(context.Background(), retry, "", "", "npm", "install")
+//         fmt.Printf("%d\n", response.ExitCode)
+//     }
+func RunShellRetry(ctx context.Context, retry Retry, shell string, shellflag string, command ...string) RetryResponse {
+	return NewShellCommand(shell, shellflag, command...).RunRetry(ctx, retry)
+}
+
+// RunRetry runs the command with the Retry policy.  Each attempt runs with the same behavior as Run.  An InputReader
+// Command.Stdin can only be passed to the first attempt.
+func (c *Command) RunRetry(ctx context.Context, retry Retry) RetryResponse {
+	attempts := retry.Attempts
+	if attempts <= 0 {
+		attempts = 3
+	}
+	retryIf := retry.RetryIf
+	if retryIf == nil {
+		retryIf = retryOnError
+	}
+	var result RetryResponse
+	for attempt := 1; ; attempt++ {
+		result.Response = c.Run(ctx)
+		result.Attempts = append(result.Attempts, result.Response)
+		if attempt >= attempts || ctx.Err() != nil || !retryIf(result.Response) {
+			return result
+		}
+		// wait for the backoff delay unless the context is done first
+		timer := time.NewTimer(retry.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the delay after the attempt (1 = the first attempt) with the multiplier, maximum, and jitter applied
+func (r Retry) backoff(attempt int) time.Duration {
+	multiplier := r.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+	delay := float64(r.Delay) * math.Pow(multiplier, float64(attempt-1))
+	// the maximum is applied before the jitter so that the delays still vary once they reach it
+	capped := r.MaxDelay > 0 && delay >= float64(r.MaxDelay)
+	if capped {
+		delay = float64(r.MaxDelay)
+	}
+	if r.Jitter > 0 {
+		jitter := math.Min(r.Jitter, 1)
+		if capped {
+			// the capped delay is only randomized downwards so that it never exceeds the maximum
+			delay -= delay * jitter * rand.Float64()
+		} else {
+			delay += delay * jitter * (2*rand.Float64() - 1)
+		}
+	}
+	if r.MaxDelay > 0 && delay > float64(r.MaxDelay) {
+		delay = float64(r.MaxDelay)
+	}
+	// a delay that grows past the largest time.Duration would overflow to a negative value
+	if delay >= math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+
+	return time.Duration(delay)
+}
+
+// retryOnError is the default Retry.RetryIf predicate.  A missing executable is not retried.
+func retryOnError(res Response) bool {
+	var lookupErr *LookupError
+	return res.Err != nil && !errors.As(res.Err, &lookupErr)
+}
+
+// RetryOnExitCodes returns a Retry.RetryIf predicate that retries a Response with one of the exit status codes
+func RetryOnExitCodes(codes ...int) func(Response) bool {
+	return func(res Response) bool {
+		return res.ExitCode != 0 && slices.Contains(codes, res.ExitCode)
+	}
+}
+
+// RetryOnStdErr returns a Retry.RetryIf predicate that retries a failed Response with standard error stream data that
+// matches the regular expression
+func RetryOnStdErr(re *regexp.Regexp) func(Response) bool {
+	return func(res Response) bool {
+		return res.Err != nil && re.MatchString(res.StdErr)
+	}
+}
+
+// RetryOnSignals returns a Retry.RetryIf predicate that retries a Response of a process that was terminated by one of
+// the signals
+func RetryOnSignals(signals ...syscall.Signal) func(Response) bool {
+	return func(res Response) bool {
+		return res.Signaled && slices.Contains(signals, res.Signal)
+	}
+}
@@ -0,0 +1,106 @@
+package subprocess
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRunShellRetry(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		counter := filepath.Join(t.TempDir(), "counter")
+		script := "n=$(cat " + counter + " 2>/dev/null || echo 0); n=$((n+1)); echo $n > " + counter + "; echo attempt $n; [ $n -ge 3 ]"
+		response := RunShellRetry(context.Background(), Retry{Attempts: 5, Delay: time.Millisecond}, "", "", script)
+		if len(response.Attempts) != 3 {
+			t.Errorf("[FAIL] Expected 3 attempts, but received %d", len(response.Attempts))
+		}
+		if response.ExitCode != 0 || response.StdOut != "attempt 3\n" {
+			t.Errorf("[FAIL] Expected the Response of the last attempt, but received %d '%s'", response.ExitCode, response.StdOut)
+		}
+		if len(response.Attempts) == 3 && (response.Attempts[0].ExitCode != 1 || response.Attempts[0].StdOut != "attempt 1\n") {
+			t.Errorf("[FAIL] Expected the Response of the first attempt, but received %d '%s'", response.Attempts[0].ExitCode, response.Attempts[0].StdOut)
+		}
+	}
+}
+
+func TestRunRetryAttempts(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		response := RunRetry(context.Background(), Retry{}, "false")
+		if len(response.Attempts) != 3 || response.ExitCode != 1 {
+			t.Errorf("[FAIL] Expected 3 failed attempts by default, but received %d (exit code %d)", len(response.Attempts), response.ExitCode)
+		}
+		response = RunRetry(context.Background(), Retry{Attempts: 5}, "bogusexecutable")
+		if len(response.Attempts) != 1 {
+			t.Errorf("[FAIL] Expected no retries for a missing executable, but received %d attempts", len(response.Attempts))
+		}
+	}
+}
+
+func TestRetryPredicates(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		retry := Retry{Attempts: 3, RetryIf: RetryOnExitCodes(2, 75)}
+		if response := RunShellRetry(context.Background(), retry, "", "", "exit 1"); len(response.Attempts) != 1 {
+			t.Errorf("[FAIL] Expected no retries for exit code 1, but received %d attempts", len(response.Attempts))
+		}
+		if response := RunShellRetry(context.Background(), retry, "", "", "exit 75"); len(response.Attempts) != 3 {
+			t.Errorf("[FAIL] Expected retries for exit code 75, but received %d attempts", len(response.Attempts))
+		}
+		retry.RetryIf = RetryOnStdErr(regexp.MustCompile("connection reset"))
+		if response := RunShellRetry(context.Background(), retry, "", "", "echo 'error: connection reset' >&2; exit 1"); len(response.Attempts) != 3 {
+			t.Errorf("[FAIL] Expected retries for a matching stderr, but received %d attempts", len(response.Attempts))
+		}
+		retry.RetryIf = RetryOnSignals(syscall.SIGTERM)
+		if response := RunShellRetry(context.Background(), retry, "", "", "kill -TERM $$"); len(response.Attempts) != 3 {
+			t.Errorf("[FAIL] Expected retries for SIGTERM, but received %d attempts", len(response.Attempts))
+		}
+	}
+}
+
+func TestRunRetryContext(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		response := RunRetry(ctx, Retry{Attempts: 5, Delay: 10 * time.Second}, "false")
+		if len(response.Attempts) != 1 || time.Since(start) > 5*time.Second {
+			t.Errorf("[FAIL] Expected the backoff delay to end with the context, but received %d attempts", len(response.Attempts))
+		}
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	retry := Retry{Delay: 100 * time.Millisecond, MaxDelay: time.Second}
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second}
+	for i, delay := range expected {
+		if retry.backoff(i+1) != delay {
+			t.Errorf("[FAIL] Expected backoff %v after attempt %d, but received %v", delay, i+1, retry.backoff(i+1))
+		}
+	}
+	retry.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if delay := retry.backoff(1); delay < 50*time.Millisecond || delay > 150*time.Millisecond {
+			t.Errorf("[FAIL] Expected a backoff from 50ms to 150ms with jitter, but received %v", delay)
+		}
+	}
+	// the capped delays are randomized below the maximum delay
+	capped := make(map[time.Duration]bool)
+	for i := 0; i < 100; i++ {
+		delay := retry.backoff(5 + i%3)
+		if delay < 500*time.Millisecond || delay > time.Second {
+			t.Errorf("[FAIL] Expected a capped backoff from 500ms to 1s with jitter, but received %v", delay)
+		}
+		capped[delay] = true
+	}
+	if len(capped) < 2 {
+		t.Errorf("[FAIL] Expected the capped backoff delays to vary with jitter, but received %v", capped)
+	}
+	retry = Retry{Delay: time.Second}
+	if delay := retry.backoff(40); delay != time.Duration(math.MaxInt64) {
+		t.Errorf("[FAIL] Expected the backoff without a maximum delay to not overflow, but received %v", delay)
+	}
+}
@@ -0,0 +1,22 @@
+package subprocess
+
+import (
+	"context"
+)
+
+// Runner is the interface that runs a Command and returns its Response.  Depend on a Runner instead of the public Run
+// functions to replace the system processes with a FakeRunner in unit tests.
+type Runner interface {
+	Run(ctx context.Context, c *Command) Response
+}
+
+// ExecRunner is the Runner that runs a Command as a system process with Command.Run
+type ExecRunner struct{}
+
+// Run runs the command as a system process with the same behavior as Command.Run
+func (ExecRunner) Run(ctx context.Context, c *Command) Response {
+	return c.Run(ctx)
+}
+
+// DefaultRunner is the Runner that runs commands as system processes
+var DefaultRunner Runner = ExecRunner{}
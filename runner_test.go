@@ -0,0 +1,17 @@
+package subprocess
+
+import (
+	"context"
+	"runtime"
+	"testing"
+)
+
+func TestExecRunner(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		var runner Runner = DefaultRunner
+		response := runner.Run(context.Background(), NewCommand("echo", "real"))
+		if response.StdOut != "real\n" || response.ExitCode != 0 {
+			t.Errorf("[FAIL] Expected the ExecRunner to run a system process, but received '%s' (%d)", response.StdOut, response.ExitCode)
+		}
+	}
+}
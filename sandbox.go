@@ -0,0 +1,38 @@
+package subprocess
+
+import (
+	"errors"
+)
+
+// ErrSandboxUnsupported is the error that is wrapped in the *StartError of a Command with a Sandbox when the platform
+// or the kernel does not allow the current user to create the namespaces of the sandbox
+var ErrSandboxUnsupported = errors.New("subprocess: the sandbox namespaces are not supported for the current user")
+
+// Sandbox is a struct that is defined with the Linux namespaces that isolate a Command process.  Define a Sandbox with
+// all of the namespaces with the public NewSandbox function.  It includes the following data fields:
+//
+//     Sandbox.User - (bool) run the process in a new user namespace.  The user and group of the current process are
+//                    mapped to Sandbox.UID and Sandbox.GID so that no privileges are needed for the other namespaces
+//     Sandbox.UID - (int) user ID of the process in the user namespace.  Default = 0 (root in the namespace only)
+//     Sandbox.GID - (int) group ID of the process in the user namespace.  Default = 0
+//     Sandbox.Mount - (bool) run the process in a private mount namespace.  Mounts do not propagate to the system
+//     Sandbox.Network - (bool) run the process in a new network namespace with only a loopback interface (no network)
+//     Sandbox.PID - (bool) run the process in a new PID namespace as process ID 1
+//
+// The namespaces are only supported on Linux.  Unprivileged users need the kernel to allow unprivileged user
+// namespaces (e.g. the kernel.unprivileged_userns_clone and user.max_user_namespaces sysctl settings).  A *StartError
+// that wraps ErrSandboxUnsupported is returned when the namespaces cannot be created.  /proc shows the processes of the
+// system in a PID namespace until the process mounts a new proc file system.
+type Sandbox struct {
+	User    bool
+	UID     int
+	GID     int
+	Mount   bool
+	Network bool
+	PID     bool
+}
+
+// NewSandbox is a public function that returns a *Sandbox with the user, mount, network, and PID namespaces
+func NewSandbox() *Sandbox {
+	return &Sandbox{User: true, Mount: true, Network: true, PID: true}
+}
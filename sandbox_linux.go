@@ -0,0 +1,42 @@
+package subprocess
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// setSandbox defines the namespace clone flags and the user namespace ID mappings of the process
+func setSandbox(attr *syscall.SysProcAttr, sandbox *Sandbox) error {
+	if sandbox.User {
+		attr.Cloneflags |= syscall.CLONE_NEWUSER
+		attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: sandbox.UID, HostID: os.Getuid(), Size: 1}}
+		attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: sandbox.GID, HostID: os.Getgid(), Size: 1}}
+		// an unprivileged process can only map its group when setgroups is disabled
+		attr.GidMappingsEnableSetgroups = false
+	}
+	if sandbox.Mount {
+		// unshare (rather than clone) the mount namespace so that the mounts are remounted as private
+		attr.Unshareflags |= syscall.CLONE_NEWNS
+	}
+	if sandbox.Network {
+		attr.Cloneflags |= syscall.CLONE_NEWNET
+	}
+	if sandbox.PID {
+		attr.Cloneflags |= syscall.CLONE_NEWPID
+	}
+
+	return nil
+}
+
+// getSandboxError returns the error for a process that could not be started in the namespaces.  The kernel reports
+// namespaces that the user may not create with EPERM, EINVAL (not supported), ENOSPC, or EUSERS (limits).
+func getSandboxError(err error) error {
+	for _, errno := range []syscall.Errno{syscall.EPERM, syscall.EINVAL, syscall.ENOSPC, syscall.EUSERS} {
+		if errors.Is(err, errno) {
+			return fmt.Errorf("%w: %v", ErrSandboxUnsupported, err)
+		}
+	}
+	return err
+}
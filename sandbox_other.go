@@ -0,0 +1,16 @@
+//go:build !linux
+
+package subprocess
+
+import (
+	"syscall"
+)
+
+// setSandbox returns an error because namespaces are only supported on Linux
+func setSandbox(attr *syscall.SysProcAttr, sandbox *Sandbox) error {
+	return ErrSandboxUnsupported
+}
+
+func getSandboxError(err error) error {
+	return err
+}
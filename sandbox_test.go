@@ -0,0 +1,67 @@
+package subprocess
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// runSandbox runs the command in a sandbox and skips the test when the kernel does not allow the namespaces
+func runSandbox(t *testing.T, cmd *Command) Response {
+	response := cmd.Run(context.Background())
+	if errors.Is(response.Err, ErrSandboxUnsupported) {
+		if runtime.GOOS == "linux" {
+			t.Skipf("namespaces are not available: %v", response.Err)
+		}
+		return response
+	}
+	if runtime.GOOS != "linux" {
+		t.Errorf("[FAIL] Expected ErrSandboxUnsupported on %s, but received %v", runtime.GOOS, response.Err)
+	}
+	return response
+}
+
+func TestCommandSandbox(t *testing.T) {
+	cmd := NewShellCommand("", "", "id -u; id -g; echo $$; cat /proc/net/dev")
+	cmd.Sandbox = NewSandbox()
+	response := runSandbox(t, cmd)
+	if runtime.GOOS == "linux" {
+		lines := strings.Split(response.StdOut, "\n")
+		if len(lines) < 3 || lines[0] != "0" || lines[1] != "0" {
+			t.Errorf("[FAIL] Expected uid and gid 0 in the user namespace, but received '%s'", response.StdOut)
+		}
+		if len(lines) < 3 || lines[2] != "1" {
+			t.Errorf("[FAIL] Expected process ID 1 in the PID namespace, but received '%s'", response.StdOut)
+		}
+		// the new network namespace only has a loopback interface
+		if !strings.Contains(response.StdOut, "lo:") || strings.Count(response.StdOut, ":") != 1 {
+			t.Errorf("[FAIL] Expected only a loopback interface in the network namespace, but received '%s'", response.StdOut)
+		}
+	}
+}
+
+func TestCommandSandboxUIDMapping(t *testing.T) {
+	cmd := NewCommand("id", "-u")
+	cmd.Sandbox = &Sandbox{User: true, UID: 1000, GID: 1000}
+	response := runSandbox(t, cmd)
+	if runtime.GOOS == "linux" && strings.TrimSpace(response.StdOut) != "1000" {
+		t.Errorf("[FAIL] Expected uid 1000 in the user namespace, but received '%s' (%v)", response.StdOut, response.Err)
+	}
+}
+
+func TestCommandSandboxMount(t *testing.T) {
+	if runtime.GOOS == "linux" {
+		dir := t.TempDir()
+		cmd := NewShellCommand("", "", "mount -t tmpfs tmpfs "+dir+" && touch "+dir+"/inside")
+		cmd.Sandbox = &Sandbox{User: true, Mount: true}
+		response := runSandbox(t, cmd)
+		if response.ExitCode != 0 {
+			t.Skipf("tmpfs mounts are not allowed in the sandbox: %s", response.StdErr)
+		}
+		if r := Run("ls", dir); r.StdOut != "" {
+			t.Errorf("[FAIL] Expected the sandbox mount to be private, but received '%s'", r.StdOut)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+//go:build unix
+
+package subprocess
+
+import (
+	"syscall"
+)
+
+func init() {
+	for sig, name := range map[syscall.Signal]string{
+		syscall.SIGCHLD:   "SIGCHLD",
+		syscall.SIGCONT:   "SIGCONT",
+		syscall.SIGPROF:   "SIGPROF",
+		syscall.SIGSTOP:   "SIGSTOP",
+		syscall.SIGSYS:    "SIGSYS",
+		syscall.SIGTSTP:   "SIGTSTP",
+		syscall.SIGTTIN:   "SIGTTIN",
+		syscall.SIGTTOU:   "SIGTTOU",
+		syscall.SIGURG:    "SIGURG",
+		syscall.SIGUSR1:   "SIGUSR1",
+		syscall.SIGUSR2:   "SIGUSR2",
+		syscall.SIGVTALRM: "SIGVTALRM",
+		syscall.SIGWINCH:  "SIGWINCH",
+		syscall.SIGXCPU:   "SIGXCPU",
+		syscall.SIGXFSZ:   "SIGXFSZ",
+	} {
+		signalNames[sig] = name
+	}
+}
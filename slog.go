@@ -0,0 +1,86 @@
+package subprocess
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogHook is a Hook that writes the events to a log/slog Logger.  Define a SlogHook with the public NewSlogHook
+// function.  The start events are logged with the "subprocess started" message and the finish events with the
+// "subprocess finished" message at SlogHook.Level, or at SlogHook.ErrorLevel when the process failed.
+type SlogHook struct {
+	Logger     *slog.Logger
+	Level      slog.Level
+	ErrorLevel slog.Level
+}
+
+// NewSlogHook is a public function that returns a *SlogHook that logs at the info level and the failed processes at the
+// warn level.
+// NewSlogHook takes the following parameters:
+//
+//  logger (*slog.Logger) - the logger that the events are written to.  nil = slog.Default()
+//
+// Example:
+//
+//     func main() {
+//         cmd := NewCommand("terraform", "apply", "-auto-approve")
+//         cmd.Hook = NewSlogHook(slog.New(slog.NewJSONHandler(os.Stderr, nil)))
+//         cmd.Run(context.Background())
+//     }
+func NewSlogHook(logger *slog.Logger) *SlogHook {
+	return &SlogHook{Logger: logger, Level: slog.LevelInfo, ErrorLevel: slog.LevelWarn}
+}
+
+// Started logs the start event
+func (h *SlogHook) Started(ctx context.Context, e StartEvent) {
+	h.logger().LogAttrs(ctx, h.Level, "subprocess started", startAttrs(e)...)
+}
+
+// Finished logs the finish event
+func (h *SlogHook) Finished(ctx context.Context, e FinishEvent) {
+	level := h.Level
+	attrs := append(startAttrs(e.StartEvent),
+		slog.Int("exit_code", e.ExitCode),
+		slog.Duration("duration", e.Duration),
+		slog.Int64("stdout_bytes", e.StdOutBytes),
+		slog.Int64("stderr_bytes", e.StdErrBytes),
+	)
+	if e.Signal != 0 {
+		attrs = append(attrs, slog.String("signal", SignalName(e.Signal)))
+	}
+	if e.TimedOut {
+		attrs = append(attrs, slog.Bool("timed_out", true))
+	}
+	if e.Canceled {
+		attrs = append(attrs, slog.Bool("canceled", true))
+	}
+	if e.Err != nil {
+		level = h.ErrorLevel
+		attrs = append(attrs, slog.String("error", e.Err.Error()))
+	}
+	h.logger().LogAttrs(ctx, level, "subprocess finished", attrs...)
+}
+
+// logger returns the Logger of the hook or the default Logger
+func (h *SlogHook) logger() *slog.Logger {
+	if h.Logger == nil {
+		return slog.Default()
+	}
+	return h.Logger
+}
+
+// startAttrs returns the log attributes of a start event
+func startAttrs(e StartEvent) []slog.Attr {
+	attrs := []slog.Attr{slog.String("path", e.Path), slog.Any("args", e.Args)}
+	if e.Dir != "" {
+		attrs = append(attrs, slog.String("dir", e.Dir))
+	}
+	if e.Env != nil {
+		attrs = append(attrs, slog.Any("env", e.Env))
+	}
+	if e.PID != 0 {
+		attrs = append(attrs, slog.Int("pid", e.PID))
+	}
+
+	return attrs
+}
@@ -0,0 +1,37 @@
+package subprocess
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSlogHook(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		var buf bytes.Buffer
+		cmd := NewCommand("sh", "-c", "exit 2", "--token", "abc")
+		cmd.Hook = NewSlogHook(slog.New(slog.NewJSONHandler(&buf, nil)))
+		cmd.Run(context.Background())
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("[FAIL] Expected two log records, but received %d: %s", len(lines), buf.String())
+		}
+		var started, finished map[string]any
+		json.Unmarshal([]byte(lines[0]), &started)
+		json.Unmarshal([]byte(lines[1]), &finished)
+		if started["msg"] != "subprocess started" || started["level"] != "INFO" || started["pid"] == nil {
+			t.Errorf("[FAIL] Expected an info start record with the pid, but received %s", lines[0])
+		}
+		if finished["msg"] != "subprocess finished" || finished["level"] != "WARN" || finished["exit_code"] != float64(2) {
+			t.Errorf("[FAIL] Expected a warn finish record with exit code 2, but received %s", lines[1])
+		}
+		if strings.Contains(buf.String(), `"abc"`) {
+			t.Errorf("[FAIL] Expected the token argument to be redacted, but received %s", buf.String())
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package subprocess
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const (
+	// waitid options that are not defined in the syscall package
+	waitStopped = 0x2
+	waitExited  = 0x4
+	waitNoWait  = 0x1000000
+	// waitid idtype of a process ID
+	waitPID = 1
+	// offset of si_pid in the siginfo_t struct.  si_status follows si_pid and si_uid.
+	siginfoPID = 8 + unsafe.Sizeof(uintptr(0))
+)
+
+// waitStops waits until the process has exited without reaping it and returns whether it was stopped by a signal while
+// it ran and the last stop signal.  exec.Cmd only waits for the exit of the process, so the stops are consumed here.
+func (p *Process) waitStops() (bool, syscall.Signal) {
+	pid := p.cmd.Process.Pid
+	stopped := false
+	var sig syscall.Signal
+	for {
+		var info [128]byte
+		// the process is left waitable so that exec.Cmd reaps it
+		if errno := waitid(pid, &info, waitExited|waitStopped|waitNoWait); errno != 0 {
+			return stopped, sig
+		}
+		// consume the stop.  Nothing is returned when the process has exited.
+		info = [128]byte{}
+		if errno := waitid(pid, &info, waitStopped|syscall.WNOHANG); errno != 0 {
+			return stopped, sig
+		}
+		if *(*int32)(unsafe.Pointer(&info[siginfoPID])) == 0 {
+			return stopped, sig
+		}
+		stopped = true
+		sig = syscall.Signal(*(*int32)(unsafe.Pointer(&info[siginfoPID+8])))
+	}
+}
+
+// waitid calls the waitid system call for the process and retries when it is interrupted
+func waitid(pid int, info *[128]byte, options int) syscall.Errno {
+	for {
+		_, _, errno := syscall.Syscall6(syscall.SYS_WAITID, waitPID, uintptr(pid), uintptr(unsafe.Pointer(info)),
+			uintptr(options), 0, 0)
+		if errno != syscall.EINTR {
+			return errno
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package subprocess
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRunContextStopped(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	response := RunShellContext(ctx, "", "", "kill -STOP $$")
+	if !response.Stopped || response.StopSignal != syscall.SIGSTOP {
+		t.Errorf("[FAIL] Expected the process to be stopped by SIGSTOP, but received %t and %v", response.Stopped, response.StopSignal)
+	}
+	if !response.TimedOut || response.Signal != syscall.SIGKILL {
+		t.Errorf("[FAIL] Expected the stopped process to be killed at the deadline, but received %s", response.String())
+	}
+}
+
+func TestProcessStoppedAndContinued(t *testing.T) {
+	p, err := StartShell(context.Background(), "", "", "kill -TSTP $$; echo continued")
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	p.Signal(syscall.SIGCONT)
+	response, _ := p.Wait()
+	if !response.Stopped || response.StopSignal != syscall.SIGTSTP || response.ExitCode != 0 {
+		t.Errorf("[FAIL] Expected the process to be stopped by SIGTSTP and then exit, but received %t, %v, and %s", response.Stopped, response.StopSignal, response.String())
+	}
+	if response.StdOut != "continued\n" {
+		t.Errorf("[FAIL] Expected the process to continue, but received '%s'", response.StdOut)
+	}
+
+	response = Run("true")
+	if response.Stopped || response.StopSignal != 0 {
+		t.Errorf("[FAIL] Expected a process that was not stopped to report no stop, but received %v", response.StopSignal)
+	}
+}
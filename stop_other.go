@@ -0,0 +1,12 @@
+//go:build !linux
+
+package subprocess
+
+import (
+	"syscall"
+)
+
+// waitStops returns false because stopped processes are only reported on Linux
+func (p *Process) waitStops() (bool, syscall.Signal) {
+	return false, 0
+}
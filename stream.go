@@ -0,0 +1,134 @@
+package subprocess
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+)
+
+// Stream is a struct that is defined with handlers that receive the standard output and standard error stream data of
+// a process while it runs.  It is used with the public RunStream and RunShellStream functions and includes the following
+// data fields:
+//
+//     Stream.StdOutLine - (func(string)) called with each line of standard output, without the line ending
+//     Stream.StdErrLine - (func(string)) called with each line of standard error, without the line ending
+//     Stream.StdOutChunk - (func([]byte)) called with each chunk of standard output as it is written
+//     Stream.StdErrChunk - (func([]byte)) called with each chunk of standard error as it is written
+//     Stream.Discard - (bool) leave Response.StdOut and Response.StdErr empty instead of holding the full output
+//
+// Nil handlers are ignored.  Standard output and standard error handlers may be called concurrently.  Chunk handlers
+// must not retain the byte slice after they return.
+type Stream struct {
+	StdOutLine  func(line string)
+	StdErrLine  func(line string)
+	StdOutChunk func(chunk []byte)
+	StdErrChunk func(chunk []byte)
+	Discard     bool
+}
+
+// RunStream is a public function that executes a system command with the same behavior as RunContext and passes the
+// standard output and standard error stream data to the handlers in the stream parameter while the process runs.
+// RunStream takes the following parameters:
+//
+//  ctx (context.Context) - the context that bounds the execution of the command
+//  stream (Stream) - the handlers that receive the standard output and standard error stream data
+//  executable (string) - the executable for the command
+//  args (...string) - one or more arguments to the executable as a comma-delimited list of parameters
+//
+// Example:
+//
+//     func main() {
+//         stream := Stream{
+//             StdOutLine: func(line string) { fmt.Println("out:", line) },
+//             StdErrLine: func(line string) { fmt.Println("err:", line) },
+//         }
+//         response := RunStream(context.Background(), stream, "go", "build", "-v", "./...")
+//         fmt.Printf("%d\n", response.ExitCode)
+//     }
+func RunStream(ctx context.Context, stream Stream, executable string, args ...string) Response {
+	c := NewCommand(executable, args...)
+	c.Stream = stream
+
+	return c.Run(ctx)
+}
+
+// RunShellStream is a public function that executes a system command with a shell with the same behavior as
+// RunShellContext and passes the standard output and standard error stream data to the handlers in the stream parameter
+// while the process runs.
+// RunShellStream takes the following parameters:
+//
+//  ctx (context.Context) - the context that bounds the execution of the command
+//  stream (Stream) - the handlers that receive the standard output and standard error stream data
+//  shell (string) - path to the shell.  Defaults = /bin/sh on Linux, macOS; cmd.exe on Windows
+//  shellflag (string) - flag to run executable file with shell. Default = `-c` (macOS/Linux); `/C` (Win)
+//  command (...string) - one or more executable commands, comma-delimited parameter format
+//
+// Example (macOS/Linux):
+//
+//     func main() {
+//         stream := Stream{StdOutLine: func(line string) { fmt.Println(line) }, Discard: true}
+//         response := RunShellStream(context.Background(), stream, "", "", "tail -n 100 /var/log/syslog")
+//         fmt.Printf("%d\n", response.ExitCode)
+//     }
+func RunShellStream(ctx context.Context, stream Stream, shell string, shellflag string, command ...string) Response {
+	c := NewShellCommand(shell, shellflag, command...)
+	c.Stream = stream
+
+	return c.Run(ctx)
+}
+
+// chunkWriter is an io.Writer that passes each write to a Stream chunk handler
+type chunkWriter func(chunk []byte)
+
+func (w chunkWriter) Write(p []byte) (int, error) {
+	w(p)
+	return len(p), nil
+}
+
+// lineWriter is an io.Writer that passes each complete line of the data written to a Stream line handler.  Data that
+// does not end with a line ending is held until the next write or the final call to flush.
+type lineWriter struct {
+	handler func(line string)
+	buf     bytes.Buffer
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+		line := string(w.buf.Next(i + 1))
+		w.handler(strings.TrimSuffix(line[:i], "\r"))
+	}
+	return len(p), nil
+}
+
+// flush passes the data that remains after the last line ending to the line handler
+func (w *lineWriter) flush() {
+	if w.buf.Len() > 0 {
+		w.handler(strings.TrimSuffix(w.buf.String(), "\r"))
+		w.buf.Reset()
+	}
+}
+
+// getStreamWriter returns an io.Writer that copies the data written to the buffer and the defined Stream handlers, and
+// the lineWriter that must be flushed after the process completes (nil when a line handler is not defined)
+func getStreamWriter(buf *captureBuffer, lineHandler func(string), chunkHandler func([]byte)) (io.Writer, *lineWriter) {
+	var writers []io.Writer
+	var lw *lineWriter
+	if buf != nil {
+		writers = append(writers, buf)
+	}
+	if chunkHandler != nil {
+		writers = append(writers, chunkWriter(chunkHandler))
+	}
+	if lineHandler != nil {
+		lw = &lineWriter{handler: lineHandler}
+		writers = append(writers, lw)
+	}
+
+	return io.MultiWriter(writers...), lw
+}
@@ -0,0 +1,73 @@
+package subprocess
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunStreamCliMockLines(t *testing.T) {
+	var lines []string
+	stream := Stream{StdOutLine: func(line string) { lines = append(lines, line) }}
+	response := RunStream(context.Background(), stream, "climock", "--stdout", "one\ntwo\r\nthree")
+
+	if response.ExitCode != 0 {
+		t.Errorf("[FAIL] Expected mock exit code to be zero and it was %d", response.ExitCode)
+	}
+	if strings.Join(lines, ",") != "one,two,three" {
+		t.Errorf("[FAIL] Expected line handler to receive 'one,two,three' and it received '%s'", strings.Join(lines, ","))
+	}
+	if response.StdOut != "one\ntwo\r\nthree" {
+		t.Errorf("[FAIL] Expected std out to hold the full output and it was actually '%s'", response.StdOut)
+	}
+}
+
+func TestRunStreamCliMockChunksDiscard(t *testing.T) {
+	var mu sync.Mutex
+	var out, err []byte
+	stream := Stream{
+		StdOutChunk: func(chunk []byte) { mu.Lock(); out = append(out, chunk...); mu.Unlock() },
+		StdErrChunk: func(chunk []byte) { mu.Lock(); err = append(err, chunk...); mu.Unlock() },
+		Discard:     true,
+	}
+	response := RunStream(context.Background(), stream, "climock", "--stdout", "This is a test", "--stderr", "Error", "--exit", "1")
+
+	if response.ExitCode != 1 {
+		t.Errorf("[FAIL] Expected mock exit code to be one and it was %d", response.ExitCode)
+	}
+	if string(out) != "This is a test" {
+		t.Errorf("[FAIL] Expected std out chunks to be 'This is a test' and they were actually '%s'", out)
+	}
+	if string(err) != "Error" {
+		t.Errorf("[FAIL] Expected std err chunks to be 'Error' and they were actually '%s'", err)
+	}
+	if len(response.StdOut) != 0 || len(response.StdErr) != 0 {
+		t.Errorf("[FAIL] Expected discarded output to leave std out and std err empty")
+	}
+}
+
+func TestRunShellStreamUnixLinesWhileRunning(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		start := time.Now()
+		var first time.Duration
+		stream := Stream{StdErrLine: func(line string) {
+			if line == "first" {
+				first = time.Since(start)
+			}
+		}}
+		response := RunShellStream(context.Background(), stream, "", "", "echo first >&2; sleep 1; echo second >&2")
+
+		if response.ExitCode != 0 {
+			t.Errorf("[FAIL] Expected command to return 0 exit status code and instead it returned %d", response.ExitCode)
+		}
+		if first == 0 || first >= time.Second {
+			t.Errorf("[FAIL] Expected first line to be received while the process was running and it was received after %v", first)
+		}
+		if response.StdErr != "first\nsecond\n" {
+			t.Errorf("[FAIL] Expected std err to hold the full output and it was actually '%s'", response.StdErr)
+		}
+	}
+}
@@ -2,11 +2,12 @@
 package subprocess
 
 import (
-	"bytes"
+	"context"
+	"fmt"
 	"os/exec"
 	"runtime"
-	"strings"
 	"syscall"
+	"time"
 )
 
 // Response is a struct that is defined with data on the execution of the public Run and RunShell functions.  It is
@@ -15,10 +16,83 @@ import (
 //     Response.StdOut - (string) standard output stream cast to a string
 //     Response.StdErr - (string) standard error stream cast to a string
 //     Response.ExitCode - (int) executable exit status code as an integer
+//     Response.TimedOut - (bool) process was killed because the context deadline was exceeded
+//     Response.Canceled - (bool) process was killed because the context was canceled
+//     Response.Err - (error) nil on success; otherwise a *LookupError, *StartError, *ExitError, or *SignalError
+//     Response.Signaled - (bool) process was terminated by a signal
+//     Response.Signal - (syscall.Signal) signal that terminated the process
+//     Response.CoreDumped - (bool) process produced a core dump when it was terminated by a signal
+//     Response.Stopped - (bool) process was stopped by a signal (e.g. SIGSTOP or SIGTSTP) while it ran (Linux)
+//     Response.StopSignal - (syscall.Signal) signal that last stopped the process
+//     Response.Termination - (Termination) step of the termination policy that ended the process when it timed out or
+//                            was canceled
+//     Response.StdOutBytes - (int64) total number of bytes that the process wrote to the standard output stream
+//     Response.StdErrBytes - (int64) total number of bytes that the process wrote to the standard error stream
+//     Response.StdOutTruncated - (bool) Response.StdOut does not hold all of the standard output stream data
+//     Response.StdErrTruncated - (bool) Response.StdErr does not hold all of the standard error stream data
+//     Response.StdOutFile - (string) path to the CaptureSpill temporary file with all of the standard output stream data
+//     Response.StdErrFile - (string) path to the CaptureSpill temporary file with all of the standard error stream data
+//     Response.StartTime - (time.Time) time that the process was started
+//     Response.EndTime - (time.Time) time that the process completed
+//     Response.Duration - (time.Duration) wall-clock time between Response.StartTime and Response.EndTime
+//     Response.Usage - (Usage) CPU time, memory, block I/O, and context switch counts of the process
+//     Response.LimitExceeded - (bool) process was terminated because it exceeded the Command.Limits CPU time or file
+//                              size limit
+//     Response.MemoryPeak - (int64) peak memory usage of the Command.Cgroup process tree in bytes
+//     Response.OOMKilled - (bool) a process in the Command.Cgroup process tree was killed by the out-of-memory killer
 type Response struct {
-	StdOut   string
-	StdErr   string
-	ExitCode int
+	StdOut     string
+	StdErr     string
+	ExitCode   int
+	TimedOut   bool
+	Canceled   bool
+	Err        error
+	Signaled   bool
+	Signal     syscall.Signal
+	CoreDumped bool
+	Stopped    bool
+	StopSignal syscall.Signal
+
+	Termination Termination
+
+	StdOutBytes     int64
+	StdErrBytes     int64
+	StdOutTruncated bool
+	StdErrTruncated bool
+	StdOutFile      string
+	StdErrFile      string
+
+	StartTime time.Time
+	EndTime   time.Time
+	Duration  time.Duration
+	Usage     Usage
+
+	LimitExceeded bool
+	MemoryPeak    int64
+	OOMKilled     bool
+}
+
+// String returns a short summary of how the process completed (e.g. "exit status 1", "killed by SIGSEGV (core dumped)")
+func (r Response) String() string {
+	var summary string
+	switch {
+	case r.Signaled:
+		summary = "killed by " + SignalName(r.Signal)
+		if r.CoreDumped {
+			summary += " (core dumped)"
+		}
+	case r.ExitCode < 0 && r.Err != nil:
+		return r.Err.Error()
+	default:
+		summary = fmt.Sprintf("exit status %d", r.ExitCode)
+	}
+	if r.TimedOut {
+		summary += " (timed out)"
+	} else if r.Canceled {
+		summary += " (canceled)"
+	}
+
+	return summary
 }
 
 /*    ┏━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━┓
@@ -52,29 +126,31 @@ type Response struct {
 //         fmt.Printf("%d\n", response.ExitCode)
 //     }
 func Run(executable string, args ...string) Response {
-	// define function variables
-	var res Response
-	var outbuf, errbuf bytes.Buffer
-
-	// define the system executable call
-	cmd := exec.Command(executable, args...)
-	cmd.Stdout = &outbuf
-	cmd.Stderr = &errbuf
-	// execute the system command
-	err := cmd.Run()
-	// define the returned object fields with the data returned
-	res.StdOut = outbuf.String()
-	res.StdErr = errbuf.String()
-	if err != nil {
-		res.ExitCode = getErrorExitCode(err)
-	} else {
-		res.ExitCode = cmd.ProcessState.Sys().(syscall.WaitStatus).ExitStatus()
-	}
-	if res.StdErr == "" && res.ExitCode != 0 {
-		res.StdErr = err.Error() // return the error raised in standard error stream formatted as a string
-	}
+	return RunContext(context.Background(), executable, args...)
+}
 
-	return res
+// RunContext is a public function that executes a system command with the same behavior as Run.  The process is killed
+// if the context is canceled or its deadline is exceeded before the command completes on its own.  The returned
+// subprocess.Response struct reports this condition with the TimedOut and Canceled fields.
+// RunContext takes the following parameters:
+//
+//  ctx (context.Context) - the context that bounds the execution of the command
+//  executable (string) - the executable for the command
+//  args (...string) - one or more arguments to the executable as a comma-delimited list of parameters
+//
+// Example:
+//
+//     func main() {
+//         ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//         defer cancel()
+//         response := RunContext(ctx, "go", "--help")
+//         if response.TimedOut {
+//             fmt.Println("go --help timed out")
+//         }
+//         fmt.Printf("%d\n", response.ExitCode)
+//     }
+func RunContext(ctx context.Context, executable string, args ...string) Response {
+	return NewCommand(executable, args...).Run(ctx)
 }
 
 // RunShell is a public function that executes a system command with a shell and returns the standard output stream,
@@ -111,6 +187,49 @@ func Run(executable string, args ...string) Response {
 //         fmt.Printf("%d\n", response.ExitCode)
 //     }
 func RunShell(shell string, shellflag string, command ...string) Response {
+	return RunShellContext(context.Background(), shell, shellflag, command...)
+}
+
+// RunShellContext is a public function that executes a system command with a shell with the same behavior as RunShell.
+// The shell process is killed if the context is canceled or its deadline is exceeded before the command completes on
+// its own.  The returned subprocess.Response struct reports this condition with the TimedOut and Canceled fields.
+// RunShellContext takes the following parameters:
+//
+//  ctx (context.Context) - the context that bounds the execution of the command
+//  shell (string) - path to the shell.  Defaults = /bin/sh on Linux, macOS; cmd.exe on Windows
+//  shellflag (string) - flag to run executable file with shell. Default = `-c` (macOS/Linux); `/C` (Win)
+//  command (...string) - one or more executable commands, comma-delimited parameter format
+//
+// Example (macOS/Linux):
+//
+//     func main() {
+//         ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//         defer cancel()
+//         response := RunShellContext(ctx, "", "", "sleep", "10")
+//         fmt.Printf("%t\n", response.TimedOut)
+//     }
+func RunShellContext(ctx context.Context, shell string, shellflag string, command ...string) Response {
+	return NewShellCommand(shell, shellflag, command...).Run(ctx)
+}
+
+/*    ┏━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━┓
+ *    ┃                                                                              ┃
+ *    ┃                                                                              ┃
+ *    ┃                       ______     _            _                              ┃
+ *    ┃                       | ___ \   (_)          | |                             ┃
+ *    ┃                       | |_/ / __ ___   ____ _| |_ ___                        ┃
+ *    ┃                       |  __/ '__| \ \ / / _` | __/ _ \                       ┃
+ *    ┃                       | |  | |  | |\ V / (_| | ||  __/                       ┃
+ *    ┃                       \_|  |_|  |_| \_/ \__,_|\__\___|                       ┃
+ *    ┃                                                                              ┃
+ *    ┃                                                                              ┃
+ *    ┃                                                                              ┃
+ *    ┗━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━┛
+ */
+
+// getShell returns the shell and shell flag that are used by the public shell functions.  Empty parameters are defined
+// with the default values for the platform.
+func getShell(shell string, shellflag string) (string, string) {
 	// define the default shell by platform
 	if shell == "" {
 		if runtime.GOOS == "windows" {
@@ -127,46 +246,20 @@ func RunShell(shell string, shellflag string, command ...string) Response {
 			shellflag = "-c" // defined as `bash -c` calls for Windows and `/bin/sh -c` calls for *nix (including macOS)
 		}
 	}
-	// define function variables
-	var res Response
-	var outbuf, errbuf bytes.Buffer
-
-	// define the system executable call
-	shellExecString := strings.Join(command, " ")
-	cmd := exec.Command(shell, shellflag, shellExecString)
-	cmd.Stdout = &outbuf
-	cmd.Stderr = &errbuf
-	// execute the system command
-	err := cmd.Run()
-	// define the returned object fields with the data returned
-	res.StdOut = outbuf.String()
-	res.StdErr = errbuf.String()
-	if err != nil {
-		res.ExitCode = getErrorExitCode(err)
-	} else {
-		res.ExitCode = cmd.ProcessState.Sys().(syscall.WaitStatus).ExitStatus()
-	}
-	if res.StdErr == "" && res.ExitCode != 0 {
-		res.StdErr = err.Error() // return the error raised in standard error stream formatted as a string
-	}
 
-	return res
+	return shell, shellflag
 }
 
-/*    ┏━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━┓
- *    ┃                                                                              ┃
- *    ┃                                                                              ┃
- *    ┃                       ______     _            _                              ┃
- *    ┃                       | ___ \   (_)          | |                             ┃
- *    ┃                       | |_/ / __ ___   ____ _| |_ ___                        ┃
- *    ┃                       |  __/ '__| \ \ / / _` | __/ _ \                       ┃
- *    ┃                       | |  | |  | |\ V / (_| | ||  __/                       ┃
- *    ┃                       \_|  |_|  |_| \_/ \__,_|\__\___|                       ┃
- *    ┃                                                                              ┃
- *    ┃                                                                              ┃
- *    ┃                                                                              ┃
- *    ┗━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━┛
- */
+// setWaitStatus defines the signal and core dump fields of a subprocess.Response struct with the data in the wait
+// status of a completed process
+func setWaitStatus(res *Response, status syscall.WaitStatus) {
+	res.Signaled = status.Signaled()
+	res.CoreDumped = status.CoreDump()
+	// stopped processes are not reported because exec.Cmd does not wait with WUNTRACED
+	if res.Signaled {
+		res.Signal = status.Signal()
+	}
+}
 
 // getErrorExitCode returns an integer value representing the exit code status for non-zero exit code responses from
 // the executable called in the public functions in the subprocess package
@@ -175,6 +268,6 @@ func getErrorExitCode(err error) int {
 	if exitError, ok := err.(*exec.ExitError); ok {
 		return exitError.Sys().(syscall.WaitStatus).ExitStatus()
 	}
-	// fails that do not define an exec.ExitError (e.g. unable to identify executable on system PATH)
-	return 1 // assign a default non-zero fail code value of 1
+	// fails that do not define an exec.ExitError (e.g. standard stream copy errors)
+	return -1 // the exit status code is not known, see Response.Err for the error
 }
@@ -1,8 +1,12 @@
 package subprocess
 
 import (
+	"context"
+	"os"
 	"runtime"
+	"syscall"
 	"testing"
+	"time"
 )
 
 // Run() function tests
@@ -74,14 +78,185 @@ func TestRunInvalidCommandMissingExecutable(t *testing.T) {
 	if response.ExitCode == 0 {
 		t.Errorf("[FAIL] Expected invalid command to return non-0 exit status code and instead it returned %d", response.ExitCode)
 	}
-	if len(response.StdErr) == 0 {
-		t.Errorf("[FAIL] Expected invalid command to return standard error output and instead it returned an empty string")
+	if _, ok := response.Err.(*LookupError); !ok {
+		t.Errorf("[FAIL] Expected invalid command to return a *LookupError and instead it returned %#v", response.Err)
+	}
+	if len(response.StdErr) > 0 {
+		t.Errorf("[FAIL] Expected invalid command to return no standard error output and instead it returned %s.", response.StdErr)
 	}
 	if len(response.StdOut) > 0 {
 		t.Errorf("[FAIL] Expected invalid command to return no standard output but instead it returned %s.", response.StdOut)
 	}
 }
 
+func TestRunCliMockExitTwoExitError(t *testing.T) {
+	response := Run("climock", "--exit", "2")
+
+	exitError, ok := response.Err.(*ExitError)
+	if !ok {
+		t.Fatalf("[FAIL] Expected non-0 exit status code to return an *ExitError and instead it returned %#v", response.Err)
+	}
+	if exitError.ExitCode != 2 {
+		t.Errorf("[FAIL] Expected *ExitError exit code to be 2 and it was %d", exitError.ExitCode)
+	}
+}
+
+func TestRunCliMockStdoutZeroNoError(t *testing.T) {
+	response := Run("climock", "--stdout", "This is a test")
+
+	if response.Err != nil {
+		t.Errorf("[FAIL] Expected zero exit status code to return a nil error and instead it returned %v", response.Err)
+	}
+}
+
+func TestRunUnixPermissionDenied(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		f, err := os.CreateTemp("", "subprocess")
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+		defer os.Remove(f.Name())
+		response := Run(f.Name())
+
+		if _, ok := response.Err.(*StartError); !ok {
+			t.Errorf("[FAIL] Expected non-executable file to return a *StartError and instead it returned %#v", response.Err)
+		}
+		if response.ExitCode == 0 {
+			t.Errorf("[FAIL] Expected non-executable file to return non-0 exit status code and instead it returned %d", response.ExitCode)
+		}
+		if len(response.StdErr) > 0 {
+			t.Errorf("[FAIL] Expected non-executable file to return no standard error output and instead it returned %s.", response.StdErr)
+		}
+	}
+}
+
+func TestRunUnixSignaled(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		response := Run("/bin/sh", "-c", "kill -TERM $$")
+
+		if !response.Signaled {
+			t.Errorf("[FAIL] Expected Signaled to be true for a process terminated by a signal")
+		}
+		if response.Signal != syscall.SIGTERM {
+			t.Errorf("[FAIL] Expected Signal to be SIGTERM and it was %v", response.Signal)
+		}
+		if response.ExitCode != -1 {
+			t.Errorf("[FAIL] Expected signaled process exit code to be -1 and it was %d", response.ExitCode)
+		}
+		if signalError, ok := response.Err.(*SignalError); !ok || signalError.Signal != syscall.SIGTERM {
+			t.Errorf("[FAIL] Expected signaled process to return a *SignalError for SIGTERM and instead it returned %#v", response.Err)
+		}
+		if response.String() != "killed by SIGTERM" {
+			t.Errorf("[FAIL] Expected summary to be 'killed by SIGTERM' and it was '%s'", response.String())
+		}
+	}
+}
+
+func TestResponseString(t *testing.T) {
+	responses := map[string]Response{
+		"exit status 0":                   {},
+		"exit status 2":                   {ExitCode: 2},
+		"killed by SIGSEGV (core dumped)": {ExitCode: -1, Signaled: true, Signal: syscall.SIGSEGV, CoreDumped: true},
+		"killed by SIGKILL (timed out)":   {ExitCode: -1, Signaled: true, Signal: syscall.SIGKILL, TimedOut: true},
+	}
+	for expected, response := range responses {
+		if response.String() != expected {
+			t.Errorf("[FAIL] Expected summary to be '%s' and it was '%s'", expected, response.String())
+		}
+	}
+}
+
+///////////////////////////////////////////////////////////
+// RunContext() and RunShellContext() function tests
+///////////////////////////////////////////////////////////
+
+func TestRunContextCliMockStdoutZero(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	response := RunContext(ctx, "climock", "--stdout", "This is a test")
+
+	if response.ExitCode != 0 {
+		t.Errorf("[FAIL] Expected mock exit code to be zero and it was %d", response.ExitCode)
+	}
+	if response.StdOut != "This is a test" {
+		t.Errorf("[FAIL] Expected mock std out to be 'This is a test' and it was actually '%s'", response.StdOut)
+	}
+	if response.TimedOut || response.Canceled {
+		t.Errorf("[FAIL] Expected command to complete without context termination")
+	}
+}
+
+func TestRunContextUnixTimeout(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		response := RunContext(ctx, "sleep", "10")
+
+		if time.Since(start) > 5*time.Second {
+			t.Errorf("[FAIL] Expected command to be killed at the context deadline and it ran for %v", time.Since(start))
+		}
+		if response.ExitCode == 0 {
+			t.Errorf("[FAIL] Expected killed command to return non-0 exit status code and instead it returned %d", response.ExitCode)
+		}
+		if !response.TimedOut {
+			t.Errorf("[FAIL] Expected TimedOut to be true for a command that exceeded the context deadline")
+		}
+		if response.Canceled {
+			t.Errorf("[FAIL] Expected Canceled to be false for a command that exceeded the context deadline")
+		}
+	}
+}
+
+func TestRunContextDoneBeforeStart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	response := RunContext(ctx, "climock", "--stdout", "This is a test")
+	if response.ExitCode != -1 || response.Err == nil {
+		t.Errorf("[FAIL] Expected a start error for a canceled context, but received %d (%v)", response.ExitCode, response.Err)
+	}
+	if !response.Canceled || response.TimedOut {
+		t.Errorf("[FAIL] Expected Canceled to be true for a context that was canceled before the start")
+	}
+
+	ctx, cancel = context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	response = RunContext(ctx, "climock", "--stdout", "This is a test")
+	if !response.TimedOut || response.Canceled {
+		t.Errorf("[FAIL] Expected TimedOut to be true for a context that was past its deadline before the start")
+	}
+}
+
+func TestRunShellContextUnixCanceled(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(100*time.Millisecond, cancel)
+		response := RunShellContext(ctx, "", "", "exec sleep 10")
+
+		if response.ExitCode == 0 {
+			t.Errorf("[FAIL] Expected killed command to return non-0 exit status code and instead it returned %d", response.ExitCode)
+		}
+		if !response.Canceled {
+			t.Errorf("[FAIL] Expected Canceled to be true for a command with a canceled context")
+		}
+		if response.TimedOut {
+			t.Errorf("[FAIL] Expected TimedOut to be false for a command with a canceled context")
+		}
+	}
+}
+
+func TestRunShellContextCliMockExitTwo(t *testing.T) {
+	response := RunShellContext(context.Background(), "", "", "climock", "--exit", "2")
+
+	if response.ExitCode != 2 {
+		t.Errorf("[FAIL] Expected mock exit code to be 2 and it was %d", response.ExitCode)
+	}
+	if response.TimedOut || response.Canceled {
+		t.Errorf("[FAIL] Expected command to complete without context termination")
+	}
+}
+
 //////////////////////////////////////////////////////////////////////
 // RunShell() function tests - climock mock stdout/err/exit code tests
 //////////////////////////////////////////////////////////////////////
@@ -252,8 +427,11 @@ func TestRunShellUnixInvalidShell(t *testing.T) {
 		if response.ExitCode == 0 {
 			t.Errorf("[FAIL] Expected command to return non-0 exit status code and instead it returned %d", response.ExitCode)
 		}
-		if len(response.StdErr) == 0 {
-			t.Errorf("[FAIL] Expected command to return standard error output and instead it returned empty string")
+		if _, ok := response.Err.(*LookupError); !ok {
+			t.Errorf("[FAIL] Expected invalid shell to return a *LookupError and instead it returned %#v", response.Err)
+		}
+		if len(response.StdErr) > 0 {
+			t.Errorf("[FAIL] Expected command to return no standard error output and instead it returned %s.", response.StdErr)
 		}
 		if len(response.StdOut) > 0 {
 			t.Errorf("[FAIL] Expected command to return no standard output but instead it returned %s.", response.StdOut)
@@ -490,8 +668,11 @@ func TestRunShellWindowsInvalidShell(t *testing.T) {
 		if response.ExitCode == 0 {
 			t.Errorf("[FAIL] Expected command to return non-0 exit status code and instead it returned %d", response.ExitCode)
 		}
-		if len(response.StdErr) == 0 {
-			t.Errorf("[FAIL] Expected command to return standard error output and instead it returned empty string")
+		if _, ok := response.Err.(*LookupError); !ok {
+			t.Errorf("[FAIL] Expected invalid shell to return a *LookupError and instead it returned %#v", response.Err)
+		}
+		if len(response.StdErr) > 0 {
+			t.Errorf("[FAIL] Expected command to return no standard error output and instead it returned %s.", response.StdErr)
 		}
 		if len(response.StdOut) > 0 {
 			t.Errorf("[FAIL] Expected command to return no standard output but instead it returned %s.", response.StdOut)
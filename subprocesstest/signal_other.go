@@ -0,0 +1,13 @@
+//go:build !unix && !windows
+
+package subprocesstest
+
+import (
+	"errors"
+	"runtime"
+)
+
+// raise returns an error because the platform does not support signals
+func raise(name string) error {
+	return errors.New("--signal is not supported on " + runtime.GOOS)
+}
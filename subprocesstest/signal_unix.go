@@ -0,0 +1,28 @@
+//go:build unix
+
+package subprocesstest
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// raise terminates the current process with the signal name (with or without the SIG prefix) or number.  The Go
+// runtime handles many signals itself, so the process is replaced with a shell that sends the signal to itself with
+// the default signal action.
+func raise(name string) error {
+	sig := strings.TrimPrefix(strings.ToUpper(name), "SIG")
+	if _, err := strconv.Atoi(sig); err != nil {
+		for _, c := range sig {
+			if c < 'A' || c > 'Z' {
+				return fmt.Errorf("unknown signal %q", name)
+			}
+		}
+	}
+	err := syscall.Exec("/bin/sh", []string{"sh", "-c", "kill -" + sig + " $$"}, os.Environ())
+
+	return fmt.Errorf("unable to raise signal %q: %w", name, err)
+}
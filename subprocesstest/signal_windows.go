@@ -0,0 +1,10 @@
+package subprocesstest
+
+import (
+	"errors"
+)
+
+// raise returns an error because Windows does not support signals
+func raise(name string) error {
+	return errors.New("--signal is not supported on Windows")
+}
@@ -0,0 +1,158 @@
+// Package subprocesstest provides a mock command line executable for the tests of code that runs system processes.  It
+// uses the helper process pattern: the test binary is installed under the mock executable names and runs as the mock
+// when it is started with one of these names.
+//
+// Call Main from the TestMain function of the test package:
+//
+//     func TestMain(m *testing.M) {
+//         subprocesstest.Main(m, "climock")
+//     }
+//
+// The tests can then run the climock executable by name (directly or from a shell) with the following flags:
+//
+//     --stdout string - write the string to the standard output stream
+//     --stderr string - write the string to the standard error stream
+//     --exit int - exit with the exit status code.  Default = 0
+//     --sleep duration - sleep for the duration (e.g. 500ms, 2s) after the output is written
+//     --signal string - terminate the process with the signal (e.g. TERM, SIGKILL, 9) after the sleep (macOS/Linux)
+//     --stdin - copy the standard input stream to the standard output stream before the other output
+//     --env - write the environment in sorted "key=value" lines to the standard output stream
+//
+// The output is written in the order stdin, stdout, stderr, env.  The strings are written without a line ending.
+package subprocesstest
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Main is a public function that runs the mock when the test binary was started with one of the mock executable
+// names, and otherwise installs the mock executables in a temporary directory at the front of PATH, runs the tests, and
+// exits with the exit status code of the tests.  Main does not return.
+// Main takes the following parameters:
+//
+//  m (*testing.M) - the tests of the test package
+//  names (...string) - one or more mock executable names
+func Main(m *testing.M, names ...string) {
+	if slices.Contains(names, executableName(os.Args[0])) {
+		os.Exit(Mock(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+	}
+	dir, err := Install(names...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "subprocesstest: %v\n", err)
+		os.Exit(1)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// Install is a public function that installs the test binary under the mock executable names in a new temporary
+// directory and adds the directory to the front of PATH.  It returns the directory.  Remove the directory when the tests
+// are done.  Main calls Install.
+func Install(names ...string) (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	dir, err := os.MkdirTemp("", "subprocesstest-")
+	if err != nil {
+		return "", err
+	}
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if runtime.GOOS == "windows" {
+			path += ".exe"
+		}
+		if err := linkExecutable(executable, path); err != nil {
+			os.RemoveAll(dir)
+			return "", err
+		}
+	}
+	if err := os.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH")); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// Mock is a public function that runs the mock with the flags and returns the exit status code.  Main calls Mock when
+// the test binary runs as a mock executable.
+func Mock(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("subprocesstest", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	outString := flags.String("stdout", "", "write the string to the standard output stream")
+	errString := flags.String("stderr", "", "write the string to the standard error stream")
+	exitCode := flags.Int("exit", 0, "exit with the exit status code")
+	sleep := flags.Duration("sleep", 0, "sleep for the duration after the output is written")
+	signal := flags.String("signal", "", "terminate the process with the signal after the sleep")
+	echo := flags.Bool("stdin", false, "copy the standard input stream to the standard output stream")
+	env := flags.Bool("env", false, "write the environment to the standard output stream")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *echo {
+		io.Copy(stdout, stdin)
+	}
+	io.WriteString(stdout, *outString)
+	io.WriteString(stderr, *errString)
+	if *env {
+		environ := os.Environ()
+		slices.Sort(environ)
+		for _, kv := range environ {
+			io.WriteString(stdout, kv+"\n")
+		}
+	}
+	time.Sleep(*sleep)
+	if *signal != "" {
+		if err := raise(*signal); err != nil {
+			fmt.Fprintf(stderr, "subprocesstest: %v\n", err)
+			return 2
+		}
+	}
+
+	return *exitCode
+}
+
+// executableName returns the file name of an executable path without the .exe extension
+func executableName(path string) string {
+	name := filepath.Base(path)
+	if ext := filepath.Ext(name); strings.EqualFold(ext, ".exe") {
+		name = strings.TrimSuffix(name, ext)
+	}
+	return name
+}
+
+// linkExecutable links (or copies) the test binary to the mock executable path
+func linkExecutable(executable string, path string) error {
+	if runtime.GOOS != "windows" && os.Symlink(executable, path) == nil {
+		return nil
+	}
+	if os.Link(executable, path) == nil {
+		return nil
+	}
+	src, err := os.Open(executable)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+
+	return dst.Close()
+}
@@ -0,0 +1,71 @@
+package subprocesstest
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	Main(m, "mockcli")
+}
+
+func TestMockOutputAndExit(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("mockcli", "--stdout", "out", "--stderr", "err", "--exit", "3")
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Errorf("[FAIL] Expected exit code 3 from the mock, but received %v", err)
+	}
+	if stdout.String() != "out" || stderr.String() != "err" {
+		t.Errorf("[FAIL] Expected 'out' and 'err' from the mock, but received '%s' and '%s'", stdout.String(), stderr.String())
+	}
+}
+
+func TestMockStdinAndEnv(t *testing.T) {
+	cmd := exec.Command("mockcli", "--stdin", "--env")
+	cmd.Stdin = strings.NewReader("echoed\n")
+	cmd.Env = append(os.Environ(), "MOCK_TEST=value")
+	out, err := cmd.Output()
+	if err != nil || !strings.HasPrefix(string(out), "echoed\n") || !strings.Contains(string(out), "\nMOCK_TEST=value\n") {
+		t.Errorf("[FAIL] Expected the echoed stdin and the environment, but received '%s' (%v)", out, err)
+	}
+}
+
+func TestMockSleep(t *testing.T) {
+	start := time.Now()
+	if err := exec.Command("mockcli", "--sleep", "200ms").Run(); err != nil || time.Since(start) < 200*time.Millisecond {
+		t.Errorf("[FAIL] Expected the mock to sleep for 200ms, but received %v after %v", err, time.Since(start))
+	}
+}
+
+func TestMockSignal(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		err := exec.Command("mockcli", "--stdout", "partial", "--signal", "SIGTERM").Run()
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			t.Fatalf("[FAIL] Expected the mock to be terminated by a signal, but received %v", err)
+		}
+		status := exitErr.Sys().(syscall.WaitStatus)
+		if !status.Signaled() || status.Signal() != syscall.SIGTERM {
+			t.Errorf("[FAIL] Expected SIGTERM, but received %v", status)
+		}
+	}
+}
+
+func TestMockFromShell(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		out, err := exec.Command("/bin/sh", "-c", "mockcli --stdout 'from shell'").Output()
+		if err != nil || string(out) != "from shell" {
+			t.Errorf("[FAIL] Expected the shell to find the mock on PATH, but received '%s' (%v)", out, err)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package subprocess
+
+import (
+	"os/exec"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Termination is the type of the Response.Termination field.  It reports the step of the termination policy of a
+// Command that ended the process when the command timed out or was canceled.
+type Termination int
+
+const (
+	// TerminationNone - the process completed on its own without a termination signal
+	TerminationNone Termination = iota
+	// TerminationGraceful - the process exited after the Command.KillSignal signal, within the Command.GracePeriod
+	TerminationGraceful
+	// TerminationForced - the process was killed with SIGKILL
+	TerminationForced
+)
+
+// String returns the name of the termination step
+func (t Termination) String() string {
+	switch t {
+	case TerminationGraceful:
+		return "graceful"
+	case TerminationForced:
+		return "forced"
+	default:
+		return "none"
+	}
+}
+
+// terminator sends the termination signals of a Command process when the context of the command is done and records the
+// step of the termination policy that was reached
+type terminator struct {
+	cmd    *exec.Cmd
+	group  bool
+	signal syscall.Signal
+	grace  time.Duration
+
+	mu    sync.Mutex
+	step  Termination
+	timer *time.Timer
+	done  bool
+}
+
+// newTerminator returns a terminator for the termination policy of a Command
+func newTerminator(cmd *exec.Cmd, c *Command) *terminator {
+	sig := c.KillSignal
+	if sig == 0 {
+		sig = syscall.SIGTERM
+	}
+
+	return &terminator{cmd: cmd, group: c.newGroup(), signal: sig, grace: c.GracePeriod}
+}
+
+// cancel sends the termination signal to the process.  The process is killed immediately when a grace period is not
+// defined, otherwise it is killed when it does not exit within the grace period.  cancel is used as exec.Cmd.Cancel.
+func (t *terminator) cancel() error {
+	if t.grace <= 0 {
+		return t.kill()
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// Windows does not support signals other than kill
+	if runtime.GOOS == "windows" {
+		t.step = TerminationForced
+	} else {
+		t.step = TerminationGraceful
+	}
+	t.timer = time.AfterFunc(t.grace, func() { t.kill() })
+
+	return signalProcess(t.cmd.Process, t.group, t.signal)
+}
+
+// kill sends SIGKILL to the process if it has not completed
+func (t *terminator) kill() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.done {
+		return nil
+	}
+	t.step = TerminationForced
+
+	return signalProcess(t.cmd.Process, t.group, syscall.SIGKILL)
+}
+
+// stop records that the process completed and stops the grace period timer.  It returns the step of the termination
+// policy that was reached.
+func (t *terminator) stop() Termination {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.done = true
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+
+	return t.step
+}
@@ -0,0 +1,79 @@
+package subprocess
+
+import (
+	"context"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestTerminateUnixGraceful(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		cmd := NewShellCommand("", "", `trap "exit 0" TERM; sleep 10 & wait`)
+		cmd.ProcessGroup = true
+		cmd.GracePeriod = 5 * time.Second
+		cmd.Timeout = 100 * time.Millisecond
+		start := time.Now()
+		response := cmd.Run(context.Background())
+
+		if time.Since(start) > 4*time.Second {
+			t.Errorf("[FAIL] Expected process to exit at the termination signal and it ran for %v", time.Since(start))
+		}
+		if response.Termination != TerminationGraceful {
+			t.Errorf("[FAIL] Expected graceful termination and it was %v", response.Termination)
+		}
+		if response.ExitCode != 0 {
+			t.Errorf("[FAIL] Expected trap to return 0 exit status code and instead it returned %d", response.ExitCode)
+		}
+		if !response.TimedOut {
+			t.Errorf("[FAIL] Expected TimedOut to be true for a command that exceeded the timeout")
+		}
+	}
+}
+
+func TestTerminateUnixForcedAfterGracePeriod(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		// the ignored signal disposition is inherited by the sleep process
+		cmd := NewShellCommand("", "", `trap "" HUP; sleep 10 & wait`)
+		cmd.ProcessGroup = true
+		cmd.KillSignal = syscall.SIGHUP
+		cmd.GracePeriod = 200 * time.Millisecond
+		cmd.Timeout = 100 * time.Millisecond
+		start := time.Now()
+		response := cmd.Run(context.Background())
+
+		if time.Since(start) > 5*time.Second {
+			t.Errorf("[FAIL] Expected process to be killed after the grace period and it ran for %v", time.Since(start))
+		}
+		if time.Since(start) < 300*time.Millisecond {
+			t.Errorf("[FAIL] Expected process to be killed after the grace period and it was killed after %v", time.Since(start))
+		}
+		if response.Termination != TerminationForced {
+			t.Errorf("[FAIL] Expected forced termination and it was %v", response.Termination)
+		}
+		if !response.Signaled || response.Signal != syscall.SIGKILL {
+			t.Errorf("[FAIL] Expected process to be killed by SIGKILL and it was %s", response)
+		}
+	}
+}
+
+func TestTerminateUnixDefaultForced(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		cmd := NewCommand("sleep", "10")
+		cmd.Timeout = 100 * time.Millisecond
+		response := cmd.Run(context.Background())
+
+		if response.Termination != TerminationForced {
+			t.Errorf("[FAIL] Expected forced termination without a grace period and it was %v", response.Termination)
+		}
+	}
+}
+
+func TestTerminateNone(t *testing.T) {
+	response := NewCommand("climock", "--exit", "2").Run(context.Background())
+
+	if response.Termination != TerminationNone {
+		t.Errorf("[FAIL] Expected no termination for a command that completed on its own and it was %v", response.Termination)
+	}
+}
@@ -0,0 +1,30 @@
+package subprocess
+
+import (
+	"time"
+)
+
+// Usage is a struct that is defined with the resource usage of a completed process.  It is defined in Response.Usage
+// with the following data fields:
+//
+//     Usage.UserTime - (time.Duration) CPU time that the process spent in user mode
+//     Usage.SystemTime - (time.Duration) CPU time that the process spent in kernel mode
+//     Usage.MaxRSS - (int64) maximum resident set size of the process in bytes (macOS/Linux)
+//     Usage.InBlocks - (int64) number of block input operations (macOS/Linux)
+//     Usage.OutBlocks - (int64) number of block output operations (macOS/Linux)
+//     Usage.VoluntaryCtxSwitches - (int64) number of times the process gave up the CPU while it waited (macOS/Linux)
+//     Usage.InvoluntaryCtxSwitches - (int64) number of times the process was preempted (macOS/Linux)
+//
+// The values are reported by the operating system when the process is waited on and include the descendants of the
+// process that it waited on.  Windows only reports the CPU times.
+type Usage struct {
+	UserTime   time.Duration
+	SystemTime time.Duration
+	MaxRSS     int64
+
+	InBlocks  int64
+	OutBlocks int64
+
+	VoluntaryCtxSwitches   int64
+	InvoluntaryCtxSwitches int64
+}
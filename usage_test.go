@@ -0,0 +1,44 @@
+package subprocess
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestResponseTiming(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		before := time.Now()
+		response := Run("sleep", "0.2")
+		if response.StartTime.Before(before) || !response.EndTime.After(response.StartTime) {
+			t.Errorf("[FAIL] Expected ordered start and end times, but received %v and %v", response.StartTime, response.EndTime)
+		}
+		if response.Duration < 200*time.Millisecond || response.Duration != response.EndTime.Sub(response.StartTime) {
+			t.Errorf("[FAIL] Expected a duration of at least 200ms, but received %v", response.Duration)
+		}
+	}
+}
+
+func TestResponseUsage(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		response := RunShell("", "", "i=0; while [ $i -lt 20000 ]; do i=$((i+1)); done; sleep 0.05")
+		usage := response.Usage
+		if usage.UserTime+usage.SystemTime <= 0 {
+			t.Errorf("[FAIL] Expected CPU time for a busy loop, but received %v user and %v system", usage.UserTime, usage.SystemTime)
+		}
+		// the resident set size of a shell is always more than 100KB
+		if usage.MaxRSS < 100*1024 {
+			t.Errorf("[FAIL] Expected the maximum resident set size in bytes, but received %d", usage.MaxRSS)
+		}
+		if usage.VoluntaryCtxSwitches+usage.InvoluntaryCtxSwitches <= 0 {
+			t.Errorf("[FAIL] Expected context switches, but received none")
+		}
+	}
+}
+
+func TestResponseUsageStartError(t *testing.T) {
+	response := Run("bogusexecutable")
+	if !response.StartTime.IsZero() || response.Duration != 0 || response.Usage != (Usage{}) {
+		t.Errorf("[FAIL] Expected no timing or usage data when the process was not started")
+	}
+}
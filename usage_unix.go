@@ -0,0 +1,29 @@
+//go:build unix
+
+package subprocess
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+)
+
+// getUsage returns the resource usage of a completed process from its rusage data
+func getUsage(state *os.ProcessState) Usage {
+	usage := Usage{UserTime: state.UserTime(), SystemTime: state.SystemTime()}
+	rusage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok || rusage == nil {
+		return usage
+	}
+	usage.MaxRSS = int64(rusage.Maxrss)
+	// the maximum resident set size is reported in bytes on macOS and in kilobytes on other platforms
+	if runtime.GOOS != "darwin" && runtime.GOOS != "ios" {
+		usage.MaxRSS *= 1024
+	}
+	usage.InBlocks = int64(rusage.Inblock)
+	usage.OutBlocks = int64(rusage.Oublock)
+	usage.VoluntaryCtxSwitches = int64(rusage.Nvcsw)
+	usage.InvoluntaryCtxSwitches = int64(rusage.Nivcsw)
+
+	return usage
+}
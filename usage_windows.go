@@ -0,0 +1,10 @@
+package subprocess
+
+import (
+	"os"
+)
+
+// getUsage returns the resource usage of a completed process.  Windows only reports the CPU times.
+func getUsage(state *os.ProcessState) Usage {
+	return Usage{UserTime: state.UserTime(), SystemTime: state.SystemTime()}
+}